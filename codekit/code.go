@@ -3,6 +3,7 @@ package codekit
 import (
 	"errors"
 	"fmt"
+	"sync"
 )
 
 // Code the code definition for API
@@ -65,6 +66,37 @@ func Is(err error, target Code) bool {
 	return false
 }
 
+type mapping struct {
+	target error
+	code   Code
+}
+
+var (
+	mappings    []mapping
+	classifiers []func(err error) error
+	mappingMu   sync.RWMutex
+)
+
+// Map registers c as the Code for errors matching target (via errors.Is),
+// which is used by FromError. Earlier registrations take precedence.
+//
+//	codekit.Map(sqlkit.UniqueViolation, codekit.New(10001, "Record Already Exists"))
+//	codekit.Map(sqlkit.Deadlock, codekit.New(10002, "Please Try Again Later"))
+func Map(target error, c Code) {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+	mappings = append(mappings, mapping{target: target, code: c})
+}
+
+// Classifier registers fn to translate raw errors (e.g. driver errors) before matching
+// the targets registered by Map. fn should return err unchanged when it is not recognized.
+// Importing sqlkit registers sqlkit.Classify.
+func Classifier(fn func(err error) error) {
+	mappingMu.Lock()
+	defer mappingMu.Unlock()
+	classifiers = append(classifiers, fn)
+}
+
 // FromError returns a Code representation of err.
 func FromError(err error) Code {
 	if err == nil {
@@ -75,5 +107,22 @@ func FromError(err error) Code {
 	if errors.As(err, &c) {
 		return c
 	}
+
+	mappingMu.RLock()
+	defer mappingMu.RUnlock()
+
+	errs := []error{err}
+	for _, fn := range classifiers {
+		if v := fn(err); v != nil && v != err {
+			errs = append(errs, v)
+		}
+	}
+	for _, m := range mappings {
+		for _, e := range errs {
+			if errors.Is(e, m.target) {
+				return m.code
+			}
+		}
+	}
 	return Err.WithMsg(err.Error())
 }
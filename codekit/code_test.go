@@ -27,3 +27,10 @@ func TestWithMsg(t *testing.T) {
 func TestFromError(t *testing.T) {
 	assert.ErrorIs(t, FromError(errors.New("something wrong")), New(-1, "something wrong"))
 }
+
+func TestMap(t *testing.T) {
+	errNotFound := errors.New("not found")
+	Map(errNotFound, New(404, "Not Found"))
+	assert.ErrorIs(t, FromError(fmt.Errorf("find user: %w", errNotFound)), New(404, "Not Found"))
+	assert.ErrorIs(t, FromError(errors.New("oh no")), New(-1, "oh no"))
+}
@@ -2,6 +2,7 @@ package cors
 
 import (
 	"net/http"
+	"regexp"
 	"slices"
 	"strconv"
 	"strings"
@@ -17,71 +18,171 @@ const (
 	HeaderACEH = "Access-Control-Expose-Headers"
 	HeaderACAC = "Access-Control-Allow-Credentials"
 	HeaderACMA = "Access-Control-Max-Age"
+
+	HeaderACRM = "Access-Control-Request-Method"
+	HeaderACRH = "Access-Control-Request-Headers"
 )
 
+// pattern 通配符源，如：https://*.example.com
+type pattern struct {
+	prefix string
+	suffix string
+}
+
+func (p pattern) match(origin string) bool {
+	return len(origin) > len(p.prefix)+len(p.suffix) && strings.HasPrefix(origin, p.prefix) && strings.HasSuffix(origin, p.suffix)
+}
+
 type Cors struct {
 	allowOrigins     []string
+	allowRegexps     []*regexp.Regexp
+	allowOriginFunc  func(r *http.Request, origin string) bool
 	allowMethods     []string
 	allowHeaders     []string
 	exposeHeaders    []string
 	allowCredentials bool
 	maxAge           int
+
+	allowAll bool
+	origins  []string
+	patterns []pattern
 }
 
 func (c *Cors) Handler(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		origin := r.Header.Get("Origin")
-		if len(origin) != 0 {
-			w.Header().Set(HeaderVary, "Origin")
+
+		// Preflight request
+		if r.Method == http.MethodOptions && len(origin) != 0 && len(r.Header.Get(HeaderACRM)) != 0 {
+			c.preflight(w, r, origin)
+			return
 		}
 
-		// Access-Control-Allow-Origin
-		if slices.Contains(c.allowOrigins, wildcard) {
-			w.Header().Set(HeaderACAO, wildcard)
-		} else {
-			if slices.Contains(c.allowOrigins, origin) {
-				w.Header().Set(HeaderACAO, origin)
-			}
-			// Access-Control-Allow-Credentials
-			if c.allowCredentials {
-				w.Header().Set(HeaderACAC, "true")
+		// 响应随 Origin 变化，避免缓存串用
+		if !c.allowAll {
+			w.Header().Add(HeaderVary, "Origin")
+		}
+		if len(origin) != 0 && c.allowOrigin(r, origin) {
+			c.setOrigin(w, origin)
+
+			// Access-Control-Expose-Headers
+			if len(c.exposeHeaders) != 0 {
+				w.Header().Set(HeaderACEH, strings.Join(c.exposeHeaders, ", "))
 			}
 		}
 
-		// Access-Control-Allow-Methods
-		w.Header().Set(HeaderACAM, strings.Join(c.allowMethods, ", "))
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (c *Cors) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	header := w.Header()
+	header.Add(HeaderVary, "Origin")
+	header.Add(HeaderVary, HeaderACRM)
+	header.Add(HeaderVary, HeaderACRH)
+
+	if !c.allowOrigin(r, origin) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
 
-		// Access-Control-Allow-Headers
-		if slices.Contains(c.allowHeaders, wildcard) {
-			w.Header().Set(HeaderACAH, wildcard)
+	// Access-Control-Allow-Methods
+	method := strings.ToUpper(r.Header.Get(HeaderACRM))
+	if !slices.ContainsFunc(c.allowMethods, func(v string) bool { return strings.EqualFold(v, method) }) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	// Access-Control-Allow-Headers
+	headers := requestHeaders(r)
+	if slices.Contains(c.allowHeaders, wildcard) {
+		// 携带凭证时「*」不被视为通配符，需回显请求的头
+		if c.allowCredentials {
+			if len(headers) != 0 {
+				header.Set(HeaderACAH, strings.Join(headers, ", "))
+			}
 		} else {
-			w.Header().Set(HeaderACAH, strings.Join(c.allowHeaders, ", "))
+			header.Set(HeaderACAH, wildcard)
 		}
-
-		// Access-Control-Expose-Headers
-		if len(c.exposeHeaders) != 0 {
-			w.Header().Set(HeaderACEH, strings.Join(c.exposeHeaders, ", "))
+	} else {
+		for _, v := range headers {
+			if !slices.ContainsFunc(c.allowHeaders, func(s string) bool { return strings.EqualFold(s, v) }) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
 		}
-
-		// Access-Control-Max-Age
-		if c.maxAge > 0 {
-			w.Header().Set(HeaderACMA, strconv.Itoa(c.maxAge))
+		if len(c.allowHeaders) != 0 {
+			header.Set(HeaderACAH, strings.Join(c.allowHeaders, ", "))
 		}
+	}
 
-		// Preflight request
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusNoContent)
-			return
+	c.setOrigin(w, origin)
+	header.Set(HeaderACAM, strings.Join(c.allowMethods, ", "))
+
+	// Access-Control-Max-Age
+	if c.maxAge > 0 {
+		header.Set(HeaderACMA, strconv.Itoa(c.maxAge))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setOrigin 设置 Access-Control-Allow-Origin 和 Access-Control-Allow-Credentials
+func (c *Cors) setOrigin(w http.ResponseWriter, origin string) {
+	if c.allowAll {
+		w.Header().Set(HeaderACAO, wildcard)
+		return
+	}
+
+	w.Header().Set(HeaderACAO, origin)
+	if c.allowCredentials {
+		w.Header().Set(HeaderACAC, "true")
+	}
+}
+
+func (c *Cors) allowOrigin(r *http.Request, origin string) bool {
+	if c.allowAll {
+		return true
+	}
+
+	lower := strings.ToLower(origin)
+	if slices.Contains(c.origins, lower) {
+		return true
+	}
+	for _, p := range c.patterns {
+		if p.match(lower) {
+			return true
+		}
+	}
+	for _, re := range c.allowRegexps {
+		if re.MatchString(origin) {
+			return true
 		}
+	}
+	if c.allowOriginFunc != nil {
+		return c.allowOriginFunc(r, origin)
+	}
+	return false
+}
 
-		h.ServeHTTP(w, r)
-	})
+// requestHeaders 解析 Access-Control-Request-Headers
+func requestHeaders(r *http.Request) []string {
+	var headers []string
+	for _, v := range r.Header.Values(HeaderACRH) {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); len(s) != 0 {
+				headers = append(headers, http.CanonicalHeaderKey(s))
+			}
+		}
+	}
+	return headers
 }
 
-// New 创建一个 CORS 中间件，默认允许所有跨域请求
+// New 创建一个 CORS 中间件，未设置 ACAO、ACAORegexp 和 AllowOriginFunc 时允许所有源；
+// 允许所有源（未限制或「*」）时忽略 ACAC(true)：响应「*」且不返回 Access-Control-Allow-Credentials，
+// 浏览器将拒绝携带凭证的跨域请求，需携带凭证时应设置明确的源
 func New(opts ...Option) *Cors {
 	c := &Cors{
-		allowOrigins: []string{wildcard},
 		allowMethods: []string{
 			http.MethodHead,
 			http.MethodGet,
@@ -96,5 +197,26 @@ func New(opts ...Option) *Cors {
 	for _, f := range opts {
 		f(c)
 	}
+
+	for _, v := range c.allowOrigins {
+		v = strings.ToLower(v)
+		if v == wildcard {
+			c.allowAll = true
+			continue
+		}
+		if i := strings.IndexByte(v, '*'); i >= 0 {
+			c.patterns = append(c.patterns, pattern{prefix: v[:i], suffix: v[i+1:]})
+			continue
+		}
+		c.origins = append(c.origins, v)
+	}
+	// 未限制源
+	if len(c.allowOrigins) == 0 && len(c.allowRegexps) == 0 && c.allowOriginFunc == nil {
+		c.allowAll = true
+	}
+	// 携带凭证时允许任意源等同于关闭同源策略，故不允许携带凭证
+	if c.allowAll {
+		c.allowCredentials = false
+	}
 	return c
 }
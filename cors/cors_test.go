@@ -0,0 +1,121 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var next = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func serve(c *Cors, method, origin string, header map[string]string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, "/", nil)
+	if len(origin) != 0 {
+		r.Header.Set("Origin", origin)
+	}
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	c.Handler(next).ServeHTTP(w, r)
+	return w
+}
+
+func TestOrigin(t *testing.T) {
+	c := New(
+		ACAO("https://a.com", "https://*.example.com"),
+		ACAORegexp(regexp.MustCompile(`^https://[a-z]+\.test\.io$`)),
+		AllowOriginFunc(func(r *http.Request, origin string) bool { return origin == "https://func.com" }),
+		ACAC(true),
+	)
+
+	for _, origin := range []string{"https://a.com", "https://api.example.com", "https://x.test.io", "https://func.com"} {
+		w := serve(c, http.MethodGet, origin, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, origin, w.Header().Get(HeaderACAO), origin)
+		assert.Equal(t, "true", w.Header().Get(HeaderACAC), origin)
+	}
+
+	for _, origin := range []string{"https://b.com", "https://example.com", "http://api.example.com", "https://x.test.io.evil"} {
+		w := serve(c, http.MethodGet, origin, nil)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(HeaderACAO), origin)
+		assert.Empty(t, w.Header().Get(HeaderACAC), origin)
+	}
+}
+
+func TestOriginRestricted(t *testing.T) {
+	// 仅设置正则或自定义校验时不再默认允许所有源
+	for _, c := range []*Cors{
+		New(ACAORegexp(regexp.MustCompile(`^https://a\.com$`)), ACAC(true)),
+		New(AllowOriginFunc(func(r *http.Request, origin string) bool { return origin == "https://a.com" }), ACAC(true)),
+	} {
+		w := serve(c, http.MethodGet, "https://a.com", nil)
+		assert.Equal(t, "https://a.com", w.Header().Get(HeaderACAO))
+		assert.Equal(t, "true", w.Header().Get(HeaderACAC))
+
+		w = serve(c, http.MethodGet, "https://evil.com", nil)
+		assert.Empty(t, w.Header().Get(HeaderACAO))
+		assert.Empty(t, w.Header().Get(HeaderACAC))
+
+		w = serve(c, http.MethodOptions, "https://evil.com", map[string]string{HeaderACRM: "GET"})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	c := New(ACAO("https://a.com"), ACAM(http.MethodGet, http.MethodPost), ACAH("Content-Type", "X-Token"), ACMA(600))
+
+	w := serve(c, http.MethodOptions, "https://a.com", map[string]string{HeaderACRM: "POST", HeaderACRH: "content-type, x-token"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://a.com", w.Header().Get(HeaderACAO))
+	assert.Equal(t, "GET, POST", w.Header().Get(HeaderACAM))
+	assert.Equal(t, "Content-Type, X-Token", w.Header().Get(HeaderACAH))
+	assert.Equal(t, "600", w.Header().Get(HeaderACMA))
+
+	// 不允许的方法、头、源
+	w = serve(c, http.MethodOptions, "https://a.com", map[string]string{HeaderACRM: "DELETE"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(c, http.MethodOptions, "https://a.com", map[string]string{HeaderACRM: "POST", HeaderACRH: "X-Other"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = serve(c, http.MethodOptions, "https://b.com", map[string]string{HeaderACRM: "GET"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get(HeaderACAO))
+
+	// 非预检的 OPTIONS 请求交给下游处理
+	w = serve(c, http.MethodOptions, "https://a.com", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestWildcard(t *testing.T) {
+	w := serve(New(), http.MethodOptions, "https://a.com", map[string]string{HeaderACRM: "PUT", HeaderACRH: "X-Token"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get(HeaderACAO))
+	assert.Equal(t, "*", w.Header().Get(HeaderACAH))
+	assert.Empty(t, w.Header().Get(HeaderACAC))
+
+	// 允许任意源时忽略携带凭证
+	for _, c := range []*Cors{New(ACAC(true)), New(ACAO("*"), ACAC(true))} {
+		w = serve(c, http.MethodOptions, "https://a.com", map[string]string{HeaderACRM: "PUT", HeaderACRH: "X-Token"})
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "*", w.Header().Get(HeaderACAO))
+		assert.Equal(t, "*", w.Header().Get(HeaderACAH))
+		assert.Empty(t, w.Header().Get(HeaderACAC))
+
+		w = serve(c, http.MethodGet, "https://a.com", nil)
+		assert.Equal(t, "*", w.Header().Get(HeaderACAO))
+		assert.Empty(t, w.Header().Get(HeaderACAC))
+	}
+
+	// 携带凭证时回显源和请求头
+	w = serve(New(ACAO("https://a.com"), ACAC(true)), http.MethodOptions, "https://a.com", map[string]string{HeaderACRM: "PUT", HeaderACRH: "x-token, content-type"})
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://a.com", w.Header().Get(HeaderACAO))
+	assert.Equal(t, "X-Token, Content-Type", w.Header().Get(HeaderACAH))
+	assert.Equal(t, "true", w.Header().Get(HeaderACAC))
+}
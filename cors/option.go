@@ -1,14 +1,33 @@
 package cors
 
+import (
+	"net/http"
+	"regexp"
+)
+
 type Option func(c *Cors)
 
-// ACAO = Access-Control-Allow-Origin
+// ACAO = Access-Control-Allow-Origin 允许的源，支持「*」和通配子域名（如：https://*.example.com）
 func ACAO(origins ...string) Option {
 	return func(c *Cors) {
 		c.allowOrigins = origins
 	}
 }
 
+// ACAORegexp 通过正则表达式匹配允许的源（需自行锚定，如：^https://[a-z]+\.example\.com$）
+func ACAORegexp(exprs ...*regexp.Regexp) Option {
+	return func(c *Cors) {
+		c.allowRegexps = exprs
+	}
+}
+
+// AllowOriginFunc 自定义源校验（如：查询租户配置），在 ACAO 和 ACAORegexp 均不匹配时调用
+func AllowOriginFunc(fn func(r *http.Request, origin string) bool) Option {
+	return func(c *Cors) {
+		c.allowOriginFunc = fn
+	}
+}
+
 // ACAM = Access-Control-Allow-Methods
 func ACAM(methods ...string) Option {
 	return func(c *Cors) {
@@ -30,7 +49,7 @@ func ACEH(headers ...string) Option {
 	}
 }
 
-// ACAC = Access-Control-Allow-Credentials
+// ACAC = Access-Control-Allow-Credentials 需配合 ACAO（不可为「*」）、ACAORegexp 或 AllowOriginFunc，允许所有源时被忽略
 func ACAC(allow bool) Option {
 	return func(c *Cors) {
 		c.allowCredentials = allow
@@ -78,6 +78,8 @@ func VersionCompare(rangeVer, curVer string) (bool, error) {
 }
 
 // IsUniqueDuplicateError 判断是否「唯一索引冲突」错误
+//
+// Deprecated: 基于错误信息匹配，请使用 errors.Is(sqlkit.Classify(err), sqlkit.UniqueViolation)
 func IsUniqueDuplicateError(err error) bool {
 	if err == nil {
 		return false
@@ -1,6 +1,12 @@
 package helper
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type X map[string]any
 
@@ -38,3 +44,42 @@ func (s Size) String() string {
 	}
 	return fmt.Sprintf("%dB", s)
 }
+
+// Duration 时长配置，支持 time.ParseDuration 格式（如："1m30s"），纯数字的单位为秒（兼容旧配置）；
+// 基于 string 实现，JSON 和 mapstructure（WeaklyTypedInput）均可解析数字和字符串
+type Duration string
+
+// UnmarshalJSON 支持字符串和数字
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	if len(b) != 0 && b[0] != '"' {
+		var n json.Number
+		if err := json.Unmarshal(b, &n); err != nil {
+			return err
+		}
+		*d = Duration(n.String())
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*d = Duration(s)
+	return nil
+}
+
+// Value 返回 time.Duration
+func (d Duration) Value() (time.Duration, error) {
+	s := strings.TrimSpace(string(d))
+	if len(s) == 0 {
+		return 0, nil
+	}
+	if sec, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(sec * float64(time.Second)), nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	return v, nil
+}
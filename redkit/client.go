@@ -3,6 +3,10 @@ package redkit
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
 	"time"
 
 	"github.com/noble-gase/ne/helper"
@@ -25,9 +29,15 @@ end
 
 type Config struct {
 	// Addrs 地址
+	//
+	//	[单节点] 1个地址
+	//	[哨兵]   设置 MasterName 时为哨兵地址
+	//	[集群]   多个地址
 	Addrs []string `json:"addrs" mapstructure:"addrs"`
 	// Options 选项
 	Options Options `json:"options" mapstructure:"options"`
+	// Hooks 自定义 Hook，如：慢命令日志
+	Hooks []redis.Hook `json:"-" mapstructure:"-"`
 }
 
 type Options struct {
@@ -37,58 +47,160 @@ type Options struct {
 	Username string `json:"username" mapstructure:"username"`
 	// Password 密码
 	Password string `json:"password" mapstructure:"password"`
-	// DialTimeout 连接超时时间（单位：秒）
-	DialTimeout int `json:"dial_timeout" mapstructure:"dial_timeout"`
-	// ReadTimeout 读取超时时间（单位：秒）
-	ReadTimeout int `json:"read_timeout" mapstructure:"read_timeout"`
-	// WriteTimeout 写入超时时间（单位：秒）
-	WriteTimeout int `json:"write_timeout" mapstructure:"write_timeout"`
+	// ClientName 客户端名称（CLIENT SETNAME）
+	ClientName string `json:"client_name" mapstructure:"client_name"`
+	// Protocol 协议版本：2 或 3（默认）
+	Protocol int `json:"protocol" mapstructure:"protocol"`
+	// MasterName 哨兵模式的主节点名称
+	MasterName string `json:"master_name" mapstructure:"master_name"`
+	// SentinelUsername 哨兵用户名
+	SentinelUsername string `json:"sentinel_username" mapstructure:"sentinel_username"`
+	// SentinelPassword 哨兵密码
+	SentinelPassword string `json:"sentinel_password" mapstructure:"sentinel_password"`
+	// ReadOnly 集群模式下允许从节点读
+	ReadOnly bool `json:"read_only" mapstructure:"read_only"`
+	// RouteByLatency 集群模式下按延迟路由只读命令（开启后自动启用 ReadOnly）
+	RouteByLatency bool `json:"route_by_latency" mapstructure:"route_by_latency"`
+	// RouteRandomly 集群模式下随机路由只读命令（开启后自动启用 ReadOnly）
+	RouteRandomly bool `json:"route_randomly" mapstructure:"route_randomly"`
+	// MaxRetries 最大重试次数（-1 表示不重试）
+	MaxRetries int `json:"max_retries" mapstructure:"max_retries"`
+	// DialTimeout 连接超时时间，如：5s
+	DialTimeout helper.Duration `json:"dial_timeout" mapstructure:"dial_timeout"`
+	// ReadTimeout 读取超时时间，如：500ms
+	ReadTimeout helper.Duration `json:"read_timeout" mapstructure:"read_timeout"`
+	// WriteTimeout 写入超时时间，如：500ms
+	WriteTimeout helper.Duration `json:"write_timeout" mapstructure:"write_timeout"`
+	// PingTimeout 初始化时验证连接的超时时间，默认：10s
+	PingTimeout helper.Duration `json:"ping_timeout" mapstructure:"ping_timeout"`
 	// PoolSize 连接池大小
 	PoolSize int `json:"pool_size" mapstructure:"pool_size"`
-	// PoolTimeout 连接池超时时间（单位：秒）
-	PoolTimeout int `json:"pool_timeout" mapstructure:"pool_timeout"`
+	// PoolTimeout 连接池超时时间，如：4s
+	PoolTimeout helper.Duration `json:"pool_timeout" mapstructure:"pool_timeout"`
 	// MinIdleConns 最小空闲连接数
 	MinIdleConns int `json:"min_idle_conns" mapstructure:"min_idle_conns"`
 	// MaxIdleConns 最大空闲连接数
 	MaxIdleConns int `json:"max_idle_conns" mapstructure:"max_idle_conns"`
 	// MaxActiveConns 最大活跃连接数
 	MaxActiveConns int `json:"max_active_conns" mapstructure:"max_active_conns"`
-	// ConnMaxIdleTime 连接最大闲置时间（单位：秒）
-	ConnMaxIdleTime int `json:"conn_max_idle_time" mapstructure:"conn_max_idle_time"`
-	// ConnMaxLifetime 连接最大生命时长（单位：秒）
-	ConnMaxLifetime int `json:"conn_max_lifetime" mapstructure:"conn_max_lifetime"`
+	// ConnMaxIdleTime 连接最大闲置时间，如：30m
+	ConnMaxIdleTime helper.Duration `json:"conn_max_idle_time" mapstructure:"conn_max_idle_time"`
+	// ConnMaxLifetime 连接最大生命时长，如：1h
+	ConnMaxLifetime helper.Duration `json:"conn_max_lifetime" mapstructure:"conn_max_lifetime"`
+	// TLS 证书配置
+	TLS TLSOptions `json:"tls" mapstructure:"tls"`
+
+	// Deprecated: 使用 TLS.InsecureSkipVerify
+	InsecureSkipVerify bool `json:"insecure_skip_verify" mapstructure:"insecure_skip_verify"`
+}
+
+// durations 时长配置转换后的 time.Duration
+type durations struct {
+	dial        time.Duration
+	read        time.Duration
+	write       time.Duration
+	ping        time.Duration
+	pool        time.Duration
+	connMaxIdle time.Duration
+	connMaxLife time.Duration
+}
+
+// durations 将时长配置转换为 time.Duration（纯数字的单位为秒）
+func (o *Options) durations() (*durations, error) {
+	d := new(durations)
+	fields := []struct {
+		name  string
+		value helper.Duration
+		dest  *time.Duration
+	}{
+		{"dial_timeout", o.DialTimeout, &d.dial},
+		{"read_timeout", o.ReadTimeout, &d.read},
+		{"write_timeout", o.WriteTimeout, &d.write},
+		{"ping_timeout", o.PingTimeout, &d.ping},
+		{"pool_timeout", o.PoolTimeout, &d.pool},
+		{"conn_max_idle_time", o.ConnMaxIdleTime, &d.connMaxIdle},
+		{"conn_max_lifetime", o.ConnMaxLifetime, &d.connMaxLife},
+	}
+	for _, f := range fields {
+		v, err := f.value.Value()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.dest = v
+	}
+	return d, nil
+}
+
+// tls 合并旧配置 InsecureSkipVerify
+func (o *Options) tls() TLSOptions {
+	opts := o.TLS
+	if o.InsecureSkipVerify {
+		opts.InsecureSkipVerify = true
+	}
+	return opts
+}
+
+type TLSOptions struct {
+	// Enable 是否启用 TLS（设置了证书时自动启用）
+	Enable bool `json:"enable" mapstructure:"enable"`
+	// CAFile CA证书路径
+	CAFile string `json:"ca_file" mapstructure:"ca_file"`
+	// CertFile 客户端证书路径
+	CertFile string `json:"cert_file" mapstructure:"cert_file"`
+	// KeyFile 客户端私钥路径
+	KeyFile string `json:"key_file" mapstructure:"key_file"`
+	// ServerName 用于验证证书的主机名
+	ServerName string `json:"server_name" mapstructure:"server_name"`
 	// InsecureSkipVerify 是否跳过证书验证
 	InsecureSkipVerify bool `json:"insecure_skip_verify" mapstructure:"insecure_skip_verify"`
 }
 
-func NewClient(cfg *Config) (redis.UniversalClient, error) {
-	opts := &redis.UniversalOptions{
-		Addrs:           cfg.Addrs,
-		DB:              cfg.Options.DB,
-		Username:        cfg.Options.Username,
-		Password:        cfg.Options.Password,
-		DialTimeout:     time.Duration(cfg.Options.DialTimeout) * time.Second,
-		ReadTimeout:     time.Duration(cfg.Options.ReadTimeout) * time.Second,
-		WriteTimeout:    time.Duration(cfg.Options.WriteTimeout) * time.Second,
-		PoolSize:        cfg.Options.PoolSize,
-		PoolTimeout:     time.Duration(cfg.Options.PoolTimeout) * time.Second,
-		MinIdleConns:    cfg.Options.MinIdleConns,
-		MaxIdleConns:    cfg.Options.MaxIdleConns,
-		MaxActiveConns:  cfg.Options.MaxActiveConns,
-		ConnMaxIdleTime: time.Duration(cfg.Options.ConnMaxIdleTime) * time.Second,
-		ConnMaxLifetime: time.Duration(cfg.Options.ConnMaxLifetime) * time.Second,
-		MaintNotificationsConfig: &maintnotifications.Config{
-			Mode: maintnotifications.ModeDisabled,
-		},
+func (o TLSOptions) enabled() bool {
+	return o.Enable || o.InsecureSkipVerify || len(o.CAFile) != 0 || len(o.CertFile) != 0
+}
+
+func (o TLSOptions) config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         o.ServerName,
+		InsecureSkipVerify: o.InsecureSkipVerify,
+	}
+	if len(o.CAFile) != 0 {
+		b, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, errors.New("invalid ca_file: no certificate found")
+		}
+		cfg.RootCAs = pool
 	}
-	if cfg.Options.InsecureSkipVerify {
-		opts.TLSConfig = &tls.Config{
-			InsecureSkipVerify: true,
+	if len(o.CertFile) != 0 || len(o.KeyFile) != 0 {
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load key pair: %w", err)
 		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func NewClient(cfg *Config) (redis.UniversalClient, error) {
+	opts, err := universalOptions(cfg)
+	if err != nil {
+		return nil, err
 	}
 	client := redis.NewUniversalClient(opts)
+	for _, h := range cfg.Hooks {
+		client.AddHook(h)
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	timeout, _ := cfg.Options.PingTimeout.Value()
+	if timeout <= 0 {
+		timeout = time.Second * 10
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	// verify connection
@@ -98,3 +210,47 @@ func NewClient(cfg *Config) (redis.UniversalClient, error) {
 	}
 	return client, nil
 }
+
+func universalOptions(cfg *Config) (*redis.UniversalOptions, error) {
+	d, err := cfg.Options.durations()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:            cfg.Addrs,
+		DB:               cfg.Options.DB,
+		Username:         cfg.Options.Username,
+		Password:         cfg.Options.Password,
+		ClientName:       cfg.Options.ClientName,
+		Protocol:         cfg.Options.Protocol,
+		MasterName:       cfg.Options.MasterName,
+		SentinelUsername: cfg.Options.SentinelUsername,
+		SentinelPassword: cfg.Options.SentinelPassword,
+		ReadOnly:         cfg.Options.ReadOnly,
+		RouteByLatency:   cfg.Options.RouteByLatency,
+		RouteRandomly:    cfg.Options.RouteRandomly,
+		MaxRetries:       cfg.Options.MaxRetries,
+		DialTimeout:      d.dial,
+		ReadTimeout:      d.read,
+		WriteTimeout:     d.write,
+		PoolSize:         cfg.Options.PoolSize,
+		PoolTimeout:      d.pool,
+		MinIdleConns:     cfg.Options.MinIdleConns,
+		MaxIdleConns:     cfg.Options.MaxIdleConns,
+		MaxActiveConns:   cfg.Options.MaxActiveConns,
+		ConnMaxIdleTime:  d.connMaxIdle,
+		ConnMaxLifetime:  d.connMaxLife,
+		MaintNotificationsConfig: &maintnotifications.Config{
+			Mode: maintnotifications.ModeDisabled,
+		},
+	}
+	if tlsOpts := cfg.Options.tls(); tlsOpts.enabled() {
+		tlsCfg, err := tlsOpts.config()
+		if err != nil {
+			return nil, err
+		}
+		opts.TLSConfig = tlsCfg
+	}
+	return opts, nil
+}
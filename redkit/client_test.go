@@ -0,0 +1,76 @@
+package redkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	var cfg Config
+	err := json.Unmarshal([]byte(`{
+		"addrs": ["127.0.0.1:6379"],
+		"options": {
+			"dial_timeout": 5,
+			"read_timeout": "500ms",
+			"write_timeout": 0.5,
+			"conn_max_lifetime": "1h",
+			"insecure_skip_verify": true
+		}
+	}`), &cfg)
+	assert.Nil(t, err)
+
+	opts, err := universalOptions(&cfg)
+	assert.Nil(t, err)
+	// 纯数字的单位为秒（兼容旧配置）
+	assert.Equal(t, 5*time.Second, opts.DialTimeout)
+	assert.Equal(t, 500*time.Millisecond, opts.ReadTimeout)
+	assert.Equal(t, 500*time.Millisecond, opts.WriteTimeout)
+	assert.Equal(t, time.Hour, opts.ConnMaxLifetime)
+	assert.Equal(t, time.Duration(0), opts.PoolTimeout)
+	// 兼容旧配置 insecure_skip_verify
+	if assert.NotNil(t, opts.TLSConfig) {
+		assert.True(t, opts.TLSConfig.InsecureSkipVerify)
+	}
+
+	cfg.Options.DialTimeout = "5x"
+	_, err = universalOptions(&cfg)
+	assert.NotNil(t, err)
+}
+
+func TestSlowLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(logger) })
+
+	ctx := context.Background()
+	h := SlowLog(10 * time.Millisecond)
+
+	process := h.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		if cmd.Name() == "slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		return nil
+	})
+	assert.Nil(t, process(ctx, redis.NewCmd(ctx, "get", "k")))
+	assert.Empty(t, buf.String())
+	assert.Nil(t, process(ctx, redis.NewCmd(ctx, "slow", "k")))
+	assert.Contains(t, buf.String(), "slow command")
+	assert.Contains(t, buf.String(), "cmd=slow")
+
+	buf.Reset()
+	pipeline := h.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	assert.Nil(t, pipeline(ctx, []redis.Cmder{redis.NewCmd(ctx, "get", "a"), redis.NewCmd(ctx, "set", "b", 1)}))
+	assert.Contains(t, buf.String(), "slow pipeline")
+	assert.Contains(t, buf.String(), "cmds=\"[get set]\"")
+}
@@ -0,0 +1,157 @@
+package redkit
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var incrScript = redis.NewScript(`
+local v = redis.call('INCRBY', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call('TTL', KEYS[1]) == -1 then
+    redis.call('EXPIRE', KEYS[1], ARGV[2])
+end
+return v
+`)
+
+var pfaddScript = redis.NewScript(`
+local v = redis.call('PFADD', KEYS[1], unpack(ARGV, 2))
+if tonumber(ARGV[1]) > 0 and redis.call('TTL', KEYS[1]) == -1 then
+    redis.call('EXPIRE', KEYS[1], ARGV[1])
+end
+return v
+`)
+
+// Counter 基于 INCRBY 的分布式计数器，首次写入时设置过期时间
+type Counter struct {
+	uc  redis.UniversalClient
+	key string
+	ttl time.Duration
+}
+
+// Incr 计数加1，返回计数结果
+func (c *Counter) Incr(ctx context.Context) (int64, error) {
+	return c.IncrBy(ctx, 1)
+}
+
+// IncrBy 计数加n（n可为负数），返回计数结果
+func (c *Counter) IncrBy(ctx context.Context, n int64) (int64, error) {
+	return incrScript.Run(ctx, c.uc, []string{c.key}, n, seconds(c.ttl)).Int64()
+}
+
+// Get 返回当前计数，key不存在时返回0
+func (c *Counter) Get(ctx context.Context) (int64, error) {
+	v, err := c.uc.Get(ctx, c.key).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, err
+	}
+	return v, nil
+}
+
+// Reset 重置计数
+func (c *Counter) Reset(ctx context.Context) error {
+	return c.uc.Del(ctx, c.key).Err()
+}
+
+// NewCounter 返回一个计数器，ttl<=0 表示不过期
+func NewCounter(uc redis.UniversalClient, key string, ttl time.Duration) *Counter {
+	return &Counter{
+		uc:  uc,
+		key: key,
+		ttl: ttl,
+	}
+}
+
+// UniqueCounter 基于 HyperLogLog 的按天去重计数器（如：UV）
+//
+//	key格式：<prefix>:<yyyymmdd>
+//
+// 注意：Cluster 模式下，prefix 应使用 hash tag（如：{uv}），以支持多天合并统计
+type UniqueCounter struct {
+	uc     redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+	loc    *time.Location
+}
+
+// Add 记录今天的元素
+func (c *UniqueCounter) Add(ctx context.Context, elems ...string) error {
+	return c.AddAt(ctx, time.Now(), elems...)
+}
+
+// AddAt 记录指定日期的元素
+func (c *UniqueCounter) AddAt(ctx context.Context, t time.Time, elems ...string) error {
+	if len(elems) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(elems)+1)
+	args = append(args, seconds(c.ttl))
+	for _, v := range elems {
+		args = append(args, v)
+	}
+	return pfaddScript.Run(ctx, c.uc, []string{c.key(t)}, args...).Err()
+}
+
+// Count 返回今天的去重计数
+func (c *UniqueCounter) Count(ctx context.Context) (int64, error) {
+	return c.CountAt(ctx, time.Now())
+}
+
+// CountAt 返回指定日期的去重计数
+func (c *UniqueCounter) CountAt(ctx context.Context, t time.Time) (int64, error) {
+	return c.uc.PFCount(ctx, c.key(t)).Result()
+}
+
+// CountRange 返回日期区间 [from, to] 内合并后的去重计数
+func (c *UniqueCounter) CountRange(ctx context.Context, from, to time.Time) (int64, error) {
+	from = from.In(c.loc)
+	to = to.In(c.loc)
+
+	var keys []string
+	for t := from; !t.After(to) || sameDay(t, to); t = t.AddDate(0, 0, 1) {
+		keys = append(keys, c.key(t))
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	return c.uc.PFCount(ctx, keys...).Result()
+}
+
+func (c *UniqueCounter) key(t time.Time) string {
+	return c.prefix + ":" + t.In(c.loc).Format("20060102")
+}
+
+// NewUniqueCounter 返回一个按天去重计数器，ttl<=0 表示不过期；默认按 time.Local 划分日期
+func NewUniqueCounter(uc redis.UniversalClient, prefix string, ttl time.Duration, loc ...*time.Location) *UniqueCounter {
+	c := &UniqueCounter{
+		uc:     uc,
+		prefix: prefix,
+		ttl:    ttl,
+		loc:    time.Local,
+	}
+	if len(loc) != 0 && loc[0] != nil {
+		c.loc = loc[0]
+	}
+	return c
+}
+
+// seconds 将 ttl 转为秒（不足1秒按1秒），ttl<=0 返回0
+func seconds(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	sec := int64(ttl.Seconds())
+	if sec <= 0 {
+		sec = 1
+	}
+	return sec
+}
+
+func sameDay(a, b time.Time) bool {
+	y1, m1, d1 := a.Date()
+	y2, m2, d2 := b.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
@@ -0,0 +1,57 @@
+package redkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	ctx := context.Background()
+
+	uc := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs: []string{"127.0.0.1:6379"},
+		DB:    0,
+	})
+	defer uc.Del(ctx, "counter")
+
+	c := NewCounter(uc, "counter", time.Minute)
+	n, err := c.Incr(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = c.IncrBy(ctx, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(11), n)
+	n, err = c.Get(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(11), n)
+	t.Log(uc.TTL(ctx, "counter").String())
+}
+
+func TestUniqueCounter(t *testing.T) {
+	ctx := context.Background()
+
+	uc := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs: []string{"127.0.0.1:6379"},
+		DB:    0,
+	})
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+
+	c := NewUniqueCounter(uc, "uv", time.Hour)
+	defer uc.Del(ctx, c.key(now), c.key(yesterday))
+
+	assert.Nil(t, c.Add(ctx, "a", "b", "c", "a"))
+	assert.Nil(t, c.AddAt(ctx, yesterday, "c", "d"))
+
+	n, err := c.Count(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), n)
+	n, err = c.CountRange(ctx, yesterday, now)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), n)
+}
@@ -0,0 +1,56 @@
+package redkit
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type slowLog struct {
+	threshold time.Duration
+}
+
+func (h *slowLog) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h *slowLog) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		if cost := time.Since(start); cost >= h.threshold {
+			slog.LogAttrs(ctx, slog.LevelWarn, "[redkit] slow command", slog.String("cmd", cmd.Name()), slog.String("duration", cost.String()), slog.Any("error", cmd.Err()))
+		}
+		return err
+	}
+}
+
+func (h *slowLog) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		if cost := time.Since(start); cost >= h.threshold {
+			names := make([]string, 0, len(cmds))
+			for _, cmd := range cmds {
+				names = append(names, cmd.Name())
+			}
+			slog.LogAttrs(ctx, slog.LevelWarn, "[redkit] slow pipeline", slog.Any("cmds", names), slog.String("duration", cost.String()), slog.Any("error", err))
+		}
+		return err
+	}
+}
+
+// SlowLog 返回一个记录慢命令日志的 Hook
+//
+//	redkit.NewClient(&redkit.Config{
+//		Addrs: []string{"127.0.0.1:6379"},
+//		Hooks: []redis.Hook{redkit.SlowLog(100 * time.Millisecond)},
+//	})
+func SlowLog(threshold time.Duration) redis.Hook {
+	return &slowLog{threshold: threshold}
+}
@@ -0,0 +1,239 @@
+package redkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var zaddScript = redis.NewScript(`
+redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+if tonumber(ARGV[3]) > 0 and redis.call('TTL', KEYS[1]) == -1 then
+    redis.call('EXPIRE', KEYS[1], ARGV[3])
+end
+`)
+
+var zincrScript = redis.NewScript(`
+local v = redis.call('ZINCRBY', KEYS[1], ARGV[2], ARGV[1])
+if tonumber(ARGV[3]) > 0 and redis.call('TTL', KEYS[1]) == -1 then
+    redis.call('EXPIRE', KEYS[1], ARGV[3])
+end
+return v
+`)
+
+// Entry 排行榜条目
+type Entry[T any] struct {
+	// Member 成员
+	Member T
+	// Score 分数
+	Score float64
+	// Rank 排名（从1开始）
+	Rank int64
+}
+
+// Leaderboard 基于 ZSET 的排行榜，默认分数从高到低排名
+//
+// 成员使用 JSON 编码存储，T 应保证相同成员的编码结果一致（如：int64、string）
+type Leaderboard[T any] struct {
+	uc  redis.UniversalClient
+	key string
+	ttl time.Duration
+	asc bool
+}
+
+// Asc 返回分数从低到高排名的排行榜
+func (l *Leaderboard[T]) Asc() *Leaderboard[T] {
+	return &Leaderboard[T]{
+		uc:  l.uc,
+		key: l.key,
+		ttl: l.ttl,
+		asc: true,
+	}
+}
+
+// Set 设置成员分数
+func (l *Leaderboard[T]) Set(ctx context.Context, member T, score float64) error {
+	m, err := json.Marshal(member)
+	if err != nil {
+		return err
+	}
+	err = zaddScript.Run(ctx, l.uc, []string{l.key}, string(m), score, seconds(l.ttl)).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return err
+	}
+	return nil
+}
+
+// Incr 增加成员分数（delta可为负数），返回最新分数
+func (l *Leaderboard[T]) Incr(ctx context.Context, member T, delta float64) (float64, error) {
+	m, err := json.Marshal(member)
+	if err != nil {
+		return 0, err
+	}
+	return zincrScript.Run(ctx, l.uc, []string{l.key}, string(m), delta, seconds(l.ttl)).Float64()
+}
+
+// Remove 移除成员
+func (l *Leaderboard[T]) Remove(ctx context.Context, members ...T) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	values := make([]any, 0, len(members))
+	for _, v := range members {
+		m, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		values = append(values, string(m))
+	}
+	return l.uc.ZRem(ctx, l.key, values...).Err()
+}
+
+// Score 返回成员分数，成员不存在时返回 redis.Nil
+func (l *Leaderboard[T]) Score(ctx context.Context, member T) (float64, error) {
+	m, err := json.Marshal(member)
+	if err != nil {
+		return 0, err
+	}
+	return l.uc.ZScore(ctx, l.key, string(m)).Result()
+}
+
+// Rank 返回成员排名（从1开始）及分数，成员不存在时返回 redis.Nil
+func (l *Leaderboard[T]) Rank(ctx context.Context, member T) (*Entry[T], error) {
+	m, err := json.Marshal(member)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret redis.RankScore
+	if l.asc {
+		ret, err = l.uc.ZRankWithScore(ctx, l.key, string(m)).Result()
+	} else {
+		ret, err = l.uc.ZRevRankWithScore(ctx, l.key, string(m)).Result()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Entry[T]{
+		Member: member,
+		Score:  ret.Score,
+		Rank:   ret.Rank + 1,
+	}, nil
+}
+
+// Count 返回成员数量
+func (l *Leaderboard[T]) Count(ctx context.Context) (int64, error) {
+	return l.uc.ZCard(ctx, l.key).Result()
+}
+
+// Top 返回前n名
+func (l *Leaderboard[T]) Top(ctx context.Context, n int64) ([]*Entry[T], error) {
+	if n <= 0 {
+		return []*Entry[T]{}, nil
+	}
+	return l.rangeByRank(ctx, 0, n-1)
+}
+
+// Paginate 按排名分页查询
+func (l *Leaderboard[T]) Paginate(ctx context.Context, page, size int64) ([]*Entry[T], int64, error) {
+	total, err := l.Count(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		return []*Entry[T]{}, 0, nil
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	offset := (page - 1) * size
+
+	list, err := l.rangeByRank(ctx, offset, offset+size-1)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// RangeByScore 按分数区间 [min, max] 查询（按排名顺序），count<=0 表示不限制数量
+//
+// 注意：返回条目的 Rank 为 0（未计算）
+func (l *Leaderboard[T]) RangeByScore(ctx context.Context, min, max float64, offset, count int64) ([]*Entry[T], error) {
+	by := &redis.ZRangeBy{
+		Min:    fmt.Sprint(min),
+		Max:    fmt.Sprint(max),
+		Offset: offset,
+		Count:  count,
+	}
+	if count <= 0 {
+		by.Offset = 0
+		by.Count = 0
+	}
+
+	var (
+		zs  []redis.Z
+		err error
+	)
+	if l.asc {
+		zs, err = l.uc.ZRangeByScoreWithScores(ctx, l.key, by).Result()
+	} else {
+		by.Min, by.Max = by.Max, by.Min
+		zs, err = l.uc.ZRevRangeByScoreWithScores(ctx, l.key, by).Result()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l.entries(zs, -1)
+}
+
+func (l *Leaderboard[T]) rangeByRank(ctx context.Context, start, stop int64) ([]*Entry[T], error) {
+	var (
+		zs  []redis.Z
+		err error
+	)
+	if l.asc {
+		zs, err = l.uc.ZRangeWithScores(ctx, l.key, start, stop).Result()
+	} else {
+		zs, err = l.uc.ZRevRangeWithScores(ctx, l.key, start, stop).Result()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l.entries(zs, start)
+}
+
+// entries 解析成员，offset<0 时不计算排名
+func (l *Leaderboard[T]) entries(zs []redis.Z, offset int64) ([]*Entry[T], error) {
+	ret := make([]*Entry[T], 0, len(zs))
+	for i, z := range zs {
+		s, _ := z.Member.(string)
+
+		entry := &Entry[T]{Score: z.Score}
+		if err := json.Unmarshal([]byte(s), &entry.Member); err != nil {
+			return nil, fmt.Errorf("unmarshal(%s): %w", s, err)
+		}
+		if offset >= 0 {
+			entry.Rank = offset + int64(i) + 1
+		}
+		ret = append(ret, entry)
+	}
+	return ret, nil
+}
+
+// NewLeaderboard 返回一个排行榜，ttl<=0 表示不过期
+func NewLeaderboard[T any](uc redis.UniversalClient, key string, ttl time.Duration) *Leaderboard[T] {
+	return &Leaderboard[T]{
+		uc:  uc,
+		key: key,
+		ttl: ttl,
+	}
+}
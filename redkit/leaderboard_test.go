@@ -0,0 +1,45 @@
+package redkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderboard(t *testing.T) {
+	ctx := context.Background()
+
+	uc := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs: []string{"127.0.0.1:6379"},
+		DB:    0,
+	})
+	defer uc.Del(ctx, "leaderboard")
+
+	lb := NewLeaderboard[int64](uc, "leaderboard", time.Minute)
+	assert.Nil(t, lb.Set(ctx, 1, 100))
+	assert.Nil(t, lb.Set(ctx, 2, 200))
+	score, err := lb.Incr(ctx, 3, 150)
+	assert.Nil(t, err)
+	assert.Equal(t, float64(150), score)
+
+	entry, err := lb.Rank(ctx, 3)
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(2), entry.Rank)
+	}
+
+	list, total, err := lb.Paginate(ctx, 1, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, int64(2), list[0].Member)
+		assert.Equal(t, int64(3), list[1].Member)
+	}
+
+	list, err = lb.Asc().Top(ctx, 1)
+	if assert.Nil(t, err) && assert.Len(t, list, 1) {
+		assert.Equal(t, int64(1), list[0].Member)
+	}
+}
@@ -0,0 +1,495 @@
+package redkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/noble-gase/ne/closekit"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrQueueStarted 队列已启动消费
+var ErrQueueStarted = errors.New("redkit: queue already started")
+
+// 延迟任务成员格式：<uuid><payload>，uuid 固定 36 位
+var delayScript = redis.NewScript(`
+local items = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, v in ipairs(items) do
+    if tonumber(ARGV[3]) > 0 then
+        redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[3], '*', 'data', string.sub(v, 37))
+    else
+        redis.call('XADD', KEYS[2], '*', 'data', string.sub(v, 37))
+    end
+    redis.call('ZREM', KEYS[1], v)
+end
+return #items
+`)
+
+// Job 队列任务
+type Job[T any] struct {
+	// ID 消息ID
+	ID string
+	// Data 任务数据
+	Data T
+	// Deliveries 投递次数（含本次）
+	Deliveries int64
+}
+
+// Handler 任务处理函数，返回 error 时任务在可见性超时后重新投递
+type Handler[T any] func(ctx context.Context, job *Job[T]) error
+
+type queueOptions struct {
+	group       string
+	consumer    string
+	workers     int
+	batch       int64
+	block       time.Duration
+	visibility  time.Duration
+	maxDeliver  int64
+	deadLetter  string
+	maxLen      int64
+	interval    time.Duration
+	closePrior  closekit.Priority
+	closeEnable bool
+}
+
+// QueueOption 队列选项
+type QueueOption func(o *queueOptions)
+
+// WithGroup 设置消费组名称，默认：default
+func WithGroup(name string) QueueOption {
+	return func(o *queueOptions) {
+		o.group = name
+	}
+}
+
+// WithConsumer 设置消费者名称，默认：<hostname>-<pid>
+func WithConsumer(name string) QueueOption {
+	return func(o *queueOptions) {
+		o.consumer = name
+	}
+}
+
+// WithWorkers 设置并发消费的协程数，默认：1
+func WithWorkers(n int) QueueOption {
+	return func(o *queueOptions) {
+		o.workers = n
+	}
+}
+
+// WithBatchSize 设置单次拉取的消息数，默认：10
+func WithBatchSize(n int64) QueueOption {
+	return func(o *queueOptions) {
+		o.batch = n
+	}
+}
+
+// WithBlockTimeout 设置 XREADGROUP 阻塞等待时间，默认：5s
+func WithBlockTimeout(d time.Duration) QueueOption {
+	return func(o *queueOptions) {
+		o.block = d
+	}
+}
+
+// WithVisibilityTimeout 设置可见性超时时间，超时未确认的消息将被重新投递，默认：30s
+func WithVisibilityTimeout(d time.Duration) QueueOption {
+	return func(o *queueOptions) {
+		o.visibility = d
+	}
+}
+
+// WithMaxDeliveries 设置最大投递次数，超过后进入死信队列，默认：16
+func WithMaxDeliveries(n int64) QueueOption {
+	return func(o *queueOptions) {
+		o.maxDeliver = n
+	}
+}
+
+// WithDeadLetter 设置死信队列 Stream 名称，默认：<name>:dead
+func WithDeadLetter(stream string) QueueOption {
+	return func(o *queueOptions) {
+		o.deadLetter = stream
+	}
+}
+
+// WithMaxLen 设置 Stream 的近似最大长度（MAXLEN ~），默认不限制；
+// 已确认的消息不会被删除（以支持多个消费组），生产环境建议设置
+func WithMaxLen(n int64) QueueOption {
+	return func(o *queueOptions) {
+		o.maxLen = n
+	}
+}
+
+// WithPollInterval 设置延迟任务的轮询间隔，默认：1s
+func WithPollInterval(d time.Duration) QueueOption {
+	return func(o *queueOptions) {
+		o.interval = d
+	}
+}
+
+// WithCloser 启动消费时将 Stop 注册到 closekit
+func WithCloser(px closekit.Priority) QueueOption {
+	return func(o *queueOptions) {
+		o.closePrior = px
+		o.closeEnable = true
+	}
+}
+
+// Queue 基于「Redis Streams」实现的可靠任务队列
+//
+//	[Stream] <name>
+//	[Delay]  <name>:delayed
+//	[Dead]   <name>:dead
+//
+// 注意：Cluster 模式下，name 应使用 hash tag（如：{queue}），确保相关 key 位于同一 slot
+type Queue[T any] struct {
+	uc      redis.UniversalClient
+	stream  string
+	delayed string
+	opts    *queueOptions
+
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	closer sync.Once
+}
+
+// Push 投递任务，返回消息ID
+func (q *Queue[T]) Push(ctx context.Context, data T) (string, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	return q.uc.XAdd(ctx, q.xaddArgs(q.stream, map[string]any{"data": string(b)})).Result()
+}
+
+// PushDelay 投递延迟任务，任务在 delay 后进入队列
+func (q *Queue[T]) PushDelay(ctx context.Context, data T, delay time.Duration) error {
+	if delay <= 0 {
+		_, err := q.Push(ctx, data)
+		return err
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return q.uc.ZAdd(ctx, q.delayed, redis.Z{
+		Score:  float64(time.Now().Add(delay).UnixMilli()),
+		Member: uuid.New().String() + string(b),
+	}).Err()
+}
+
+// Start 启动消费（非阻塞），调用 Stop 停止
+func (q *Queue[T]) Start(ctx context.Context, fn Handler[T]) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.cancel != nil {
+		return ErrQueueStarted
+	}
+
+	// 创建消费组
+	if err := q.uc.XGroupCreateMkStream(ctx, q.stream, q.opts.group, "0").Err(); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("create group(%s): %w", q.opts.group, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	q.cancel = cancel
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		q.schedule(ctx)
+	}()
+
+	for i := 0; i < q.opts.workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.consume(ctx, fn)
+		}()
+	}
+
+	if q.opts.closeEnable {
+		// 多次 Start/Stop 时仅注册一次
+		q.closer.Do(func() {
+			closekit.Add("redkit-queue:"+q.stream, q.opts.closePrior, q.Stop)
+		})
+	}
+	return nil
+}
+
+// Stop 停止消费，等待处理中的任务完成（最长需等待一个 BlockTimeout）
+func (q *Queue[T]) Stop() error {
+	q.mutex.Lock()
+	cancel := q.cancel
+	q.cancel = nil
+	q.mutex.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	q.wg.Wait()
+	return nil
+}
+
+// schedule 将到期的延迟任务转移到 Stream
+func (q *Queue[T]) schedule(ctx context.Context) {
+	ticker := time.NewTicker(q.opts.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for {
+			n, err := delayScript.Run(ctx, q.uc, []string{q.delayed, q.stream}, time.Now().UnixMilli(), q.opts.batch, q.opts.maxLen).Int64()
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.LogAttrs(ctx, slog.LevelError, "[redkit:Queue] move delayed jobs failed", slog.String("stream", q.stream), slog.Any("error", err))
+				}
+				break
+			}
+			if n < q.opts.batch {
+				break
+			}
+		}
+	}
+}
+
+func (q *Queue[T]) consume(ctx context.Context, fn Handler[T]) {
+	var (
+		cursor    = "0-0"
+		lastClaim time.Time
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		var (
+			msgs []redis.XMessage
+			err  error
+		)
+
+		// 认领超时未确认的消息
+		if time.Since(lastClaim) >= q.opts.visibility/2 {
+			msgs, cursor, err = q.uc.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+				Stream:   q.stream,
+				Group:    q.opts.group,
+				MinIdle:  q.opts.visibility,
+				Start:    cursor,
+				Count:    q.opts.batch,
+				Consumer: q.opts.consumer,
+			}).Result()
+			if err != nil {
+				cursor = "0-0"
+				if !q.sleep(ctx, err) {
+					return
+				}
+				continue
+			}
+			if cursor == "0-0" {
+				lastClaim = time.Now()
+			}
+			for _, msg := range msgs {
+				q.handle(ctx, fn, msg, q.deliveries(ctx, msg.ID))
+			}
+			if len(msgs) != 0 {
+				continue
+			}
+		}
+
+		// 读取新消息
+		streams, err := q.uc.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    q.opts.group,
+			Consumer: q.opts.consumer,
+			Streams:  []string{q.stream, ">"},
+			Count:    q.opts.batch,
+			Block:    q.opts.block,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			if !q.sleep(ctx, err) {
+				return
+			}
+			continue
+		}
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				q.handle(ctx, fn, msg, 1)
+			}
+		}
+	}
+}
+
+func (q *Queue[T]) handle(ctx context.Context, fn Handler[T], msg redis.XMessage, deliveries int64) {
+	// 避免停止消费时中断处理中的任务
+	ctx = context.WithoutCancel(ctx)
+
+	if deliveries > q.opts.maxDeliver {
+		q.dead(ctx, msg, deliveries, errors.New("max deliveries exceeded"))
+		return
+	}
+
+	job := &Job[T]{
+		ID:         msg.ID,
+		Deliveries: deliveries,
+	}
+
+	str, _ := msg.Values["data"].(string)
+	if err := json.Unmarshal([]byte(str), &job.Data); err != nil {
+		// 数据无法解析，重试无意义
+		q.dead(ctx, msg, deliveries, fmt.Errorf("unmarshal(%s): %w", str, err))
+		return
+	}
+
+	if err := q.call(ctx, fn, job); err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "[redkit:Queue] handle job failed", slog.String("stream", q.stream), slog.String("id", msg.ID), slog.Int64("deliveries", deliveries), slog.Any("error", err))
+		if deliveries >= q.opts.maxDeliver {
+			q.dead(ctx, msg, deliveries, err)
+		}
+		return
+	}
+	q.ack(ctx, msg.ID)
+}
+
+func (q *Queue[T]) call(ctx context.Context, fn Handler[T], job *Job[T]) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panic recovered: %+v\n%s", r, string(debug.Stack()))
+		}
+	}()
+	return fn(ctx, job)
+}
+
+// deliveries 返回消息的投递次数
+func (q *Queue[T]) deliveries(ctx context.Context, id string) int64 {
+	pending, err := q.uc.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: q.stream,
+		Group:  q.opts.group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+	if err != nil || len(pending) == 0 {
+		return 1
+	}
+	return pending[0].RetryCount
+}
+
+func (q *Queue[T]) ack(ctx context.Context, id string) {
+	// 仅确认，不删除消息：同一 Stream 可能被多个消费组消费，长度由 WithMaxLen 控制
+	if err := q.uc.XAck(ctx, q.stream, q.opts.group, id).Err(); err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "[redkit:Queue] ack job failed", slog.String("stream", q.stream), slog.String("id", id), slog.Any("error", err))
+	}
+}
+
+// dead 将消息转移到死信队列
+func (q *Queue[T]) dead(ctx context.Context, msg redis.XMessage, deliveries int64, cause error) {
+	str, _ := msg.Values["data"].(string)
+	if err := q.uc.XAdd(ctx, q.xaddArgs(q.opts.deadLetter, map[string]any{
+		"id":         msg.ID,
+		"data":       str,
+		"deliveries": strconv.FormatInt(deliveries, 10),
+		"error":      cause.Error(),
+	})).Err(); err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "[redkit:Queue] dead letter failed", slog.String("stream", q.stream), slog.String("id", msg.ID), slog.Any("error", err))
+		return
+	}
+	q.ack(ctx, msg.ID)
+}
+
+func (q *Queue[T]) xaddArgs(stream string, values map[string]any) *redis.XAddArgs {
+	args := &redis.XAddArgs{
+		Stream: stream,
+		Values: values,
+	}
+	if q.opts.maxLen > 0 {
+		args.MaxLen = q.opts.maxLen
+		args.Approx = true
+	}
+	return args
+}
+
+// sleep 出错后等待片刻再重试，返回 false 表示已停止
+func (q *Queue[T]) sleep(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	slog.LogAttrs(ctx, slog.LevelError, "[redkit:Queue] read stream failed", slog.String("stream", q.stream), slog.Any("error", err))
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(time.Second):
+		return true
+	}
+}
+
+// NewQueue 返回一个基于「Redis Streams」的任务队列
+func NewQueue[T any](uc redis.UniversalClient, name string, opts ...QueueOption) *Queue[T] {
+	o := &queueOptions{
+		group:      "default",
+		workers:    1,
+		batch:      10,
+		block:      5 * time.Second,
+		visibility: 30 * time.Second,
+		maxDeliver: 16,
+		deadLetter: name + ":dead",
+		interval:   time.Second,
+	}
+	for _, f := range opts {
+		f(o)
+	}
+
+	if len(o.consumer) == 0 {
+		host, _ := os.Hostname()
+		o.consumer = host + "-" + strconv.Itoa(os.Getpid())
+	}
+	if o.workers <= 0 {
+		o.workers = 1
+	}
+	if o.batch <= 0 {
+		o.batch = 10
+	}
+	if o.block <= 0 {
+		o.block = 5 * time.Second
+	}
+	if o.visibility <= 0 {
+		o.visibility = 30 * time.Second
+	}
+	if o.maxDeliver <= 0 {
+		o.maxDeliver = 16
+	}
+	if o.interval <= 0 {
+		o.interval = time.Second
+	}
+
+	return &Queue[T]{
+		uc:      uc,
+		stream:  name,
+		delayed: name + ":delayed",
+		opts:    o,
+	}
+}
@@ -0,0 +1,49 @@
+package redkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+
+	uc := redis.NewUniversalClient(&redis.UniversalOptions{
+		Addrs: []string{"127.0.0.1:6379"},
+		DB:    0,
+	})
+	defer uc.Del(ctx, "queue", "queue:delayed", "queue:dead")
+
+	type Demo struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	q := NewQueue[*Demo](uc, "queue", WithBlockTimeout(time.Second), WithPollInterval(100*time.Millisecond))
+
+	ch := make(chan *Demo, 2)
+	err := q.Start(ctx, func(ctx context.Context, job *Job[*Demo]) error {
+		t.Logf(">> job %s: %+v", job.ID, job.Data)
+		ch <- job.Data
+		return nil
+	})
+	assert.Nil(t, err)
+	defer q.Stop()
+
+	_, err = q.Push(ctx, &Demo{ID: 1, Name: "hello"})
+	assert.Nil(t, err)
+	err = q.PushDelay(ctx, &Demo{ID: 2, Name: "world"}, 500*time.Millisecond)
+	assert.Nil(t, err)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case v := <-ch:
+			assert.Equal(t, i+1, v.ID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout")
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package result
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	MIMEJSON     = "application/json"
+	MIMEProtobuf = "application/x-protobuf"
+	MIMEXML      = "application/xml"
+)
+
+// Headers carrying code and msg for formats without an envelope (e.g. binary protobuf), msg is percent-encoded
+const (
+	HeaderCode = "X-Result-Code"
+	HeaderMsg  = "X-Result-Msg"
+)
+
+// Body is the result passed to an Encoder
+type Body struct {
+	XMLName xml.Name `json:"-" xml:"result"`
+	Code    int      `json:"code" xml:"code"`
+	Msg     string   `json:"msg" xml:"msg"`
+	Data    any      `json:"data,omitempty" xml:"data,omitempty"`
+}
+
+// Encoder encodes a result body into the pooled buffer
+type Encoder interface {
+	// Accept reports whether the encoder can encode the data, otherwise the next acceptable format is tried
+	Accept(data any) bool
+
+	// Encode writes the body into buf, response headers can be set via header
+	Encode(buf *bytes.Buffer, header http.Header, body *Body) error
+}
+
+// EncoderFunc adapts a function to an Encoder which accepts any data
+type EncoderFunc func(buf *bytes.Buffer, header http.Header, body *Body) error
+
+func (fn EncoderFunc) Accept(data any) bool {
+	return true
+}
+
+func (fn EncoderFunc) Encode(buf *bytes.Buffer, header http.Header, body *Body) error {
+	return fn(buf, header, body)
+}
+
+type registration struct {
+	mime        string
+	contentType string
+	enc         Encoder
+}
+
+var (
+	regMutex  sync.RWMutex
+	registry  []registration
+	protoJSON = protojson.MarshalOptions{}
+)
+
+// Register registers an encoder for the media types, the first one is used as Content-Type;
+// registering an existing media type replaces its encoder.
+// When Accept is absent or allows multiple formats equally, the earlier registered one wins.
+//
+//	// MessagePack (e.g. github.com/vmihailenco/msgpack/v5)
+//	result.Register(result.EncoderFunc(func(buf *bytes.Buffer, header http.Header, body *result.Body) error {
+//		enc := msgpack.NewEncoder(buf)
+//		enc.SetCustomStructTag("json")
+//		return enc.Encode(body)
+//	}), "application/msgpack", "application/x-msgpack")
+func Register(enc Encoder, mimes ...string) {
+	regMutex.Lock()
+	defer regMutex.Unlock()
+
+	if len(mimes) == 0 {
+		return
+	}
+
+	contentType := mimes[0]
+	for _, v := range mimes {
+		v = strings.ToLower(v)
+		reg := registration{mime: v, contentType: contentType, enc: enc}
+		if i := slices.IndexFunc(registry, func(reg registration) bool { return reg.mime == v }); i >= 0 {
+			registry[i] = reg
+			continue
+		}
+		registry = append(registry, reg)
+	}
+}
+
+// SetProtoJSON sets the options used to marshal proto.Message data into JSON
+//
+//	result.SetProtoJSON(protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true})
+func SetProtoJSON(opts protojson.MarshalOptions) {
+	regMutex.Lock()
+	defer regMutex.Unlock()
+	protoJSON = opts
+}
+
+func init() {
+	Register(jsonEncoder, MIMEJSON, "text/json")
+	Register(protobufEncoder{}, MIMEProtobuf, "application/protobuf", "application/vnd.google.protobuf")
+	Register(xmlEncoder, MIMEXML, "text/xml")
+}
+
+// jsonEncoder encodes proto.Message data with protojson, others with encoding/json
+var jsonEncoder = EncoderFunc(func(buf *bytes.Buffer, header http.Header, body *Body) error {
+	if msg, ok := body.Data.(proto.Message); ok {
+		regMutex.RLock()
+		opts := protoJSON
+		regMutex.RUnlock()
+
+		b, err := opts.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		body = &Body{Code: body.Code, Msg: body.Msg, Data: json.RawMessage(b)}
+	}
+
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(body)
+})
+
+var xmlEncoder xmlEnc
+
+// xmlEnc rejects data encoding/xml cannot encode (e.g. maps), so negotiation falls back to the next format
+type xmlEnc struct{}
+
+func (xmlEnc) Accept(data any) bool {
+	return xmlEncodable(reflect.ValueOf(data), 0)
+}
+
+func (xmlEnc) Encode(buf *bytes.Buffer, header http.Header, body *Body) error {
+	buf.WriteString(xml.Header)
+	return xml.NewEncoder(buf).Encode(body)
+}
+
+var (
+	xmlMarshalerType  = reflect.TypeFor[xml.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func xmlEncodable(v reflect.Value, depth int) bool {
+	if !v.IsValid() || depth > 32 {
+		return true
+	}
+
+	t := v.Type()
+	if t.Implements(xmlMarshalerType) || t.Implements(textMarshalerType) {
+		return true
+	}
+	if v.CanAddr() && (reflect.PointerTo(t).Implements(xmlMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)) {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Map, reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return false
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return true
+		}
+		return xmlEncodable(v.Elem(), depth+1)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return true
+		}
+		for i := 0; i < v.Len(); i++ {
+			if !xmlEncodable(v.Index(i), depth+1) {
+				return false
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("xml") == "-" {
+				continue
+			}
+			if !xmlEncodable(v.Field(i), depth+1) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// protobufEncoder writes proto.Message data as binary protobuf, code and msg are set as headers
+type protobufEncoder struct{}
+
+func (protobufEncoder) Accept(data any) bool {
+	if data == nil {
+		return true
+	}
+	_, ok := data.(proto.Message)
+	return ok
+}
+
+func (protobufEncoder) Encode(buf *bytes.Buffer, header http.Header, body *Body) error {
+	header.Set(HeaderCode, strconv.Itoa(body.Code))
+	header.Set(HeaderMsg, url.PathEscape(body.Msg))
+
+	if body.Data == nil {
+		return nil
+	}
+	msg, ok := body.Data.(proto.Message)
+	if !ok {
+		return errors.New("result: data is not a proto.Message")
+	}
+	b, err := proto.MarshalOptions{}.MarshalAppend(buf.AvailableBuffer(), msg)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
+
+// negotiate returns the registered encoders acceptable by the Accept header, ordered by preference
+func negotiate(accept string) []registration {
+	regMutex.RLock()
+	defer regMutex.RUnlock()
+
+	if len(accept) == 0 {
+		return nil
+	}
+
+	type candidate struct {
+		registration
+		q     float64
+		index int
+	}
+
+	var candidates []candidate
+	for i, reg := range registry {
+		if q := quality(accept, reg.mime); q > 0 {
+			candidates = append(candidates, candidate{registration: reg, q: q, index: i})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		}
+		return a.index - b.index
+	})
+
+	// Browsers accept XML over */* (e.g. text/html,application/xml;q=0.9,*/*;q=0.8), prefer JSON for them
+	if browser(accept) {
+		if i := slices.IndexFunc(candidates, func(c candidate) bool { return c.mime == MIMEJSON }); i > 0 {
+			c := candidates[i]
+			candidates = slices.Insert(slices.Delete(candidates, i, i+1), 0, c)
+		}
+	}
+
+	ret := make([]registration, 0, len(candidates))
+	for _, c := range candidates {
+		ret = append(ret, c.registration)
+	}
+	return ret
+}
+
+// browser reports whether the Accept header explicitly lists text/html
+func browser(accept string) bool {
+	for _, v := range strings.Split(accept, ",") {
+		if rng, _, err := mime.ParseMediaType(strings.TrimSpace(v)); err == nil && rng == "text/html" {
+			return true
+		}
+	}
+	return false
+}
+
+// quality returns the q-value of the media type in the Accept header, the most specific range wins
+func quality(accept, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+
+	q, specificity := 0.0, -1
+	for _, v := range strings.Split(accept, ",") {
+		rng, params, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+
+		s := -1
+		switch {
+		case rng == mediaType:
+			s = 2
+		case rng == typ+"/*":
+			s = 1
+		case rng == "*/*":
+			s = 0
+		}
+		if s <= specificity {
+			continue
+		}
+
+		specificity = s
+		q = 1
+		if v, ok := params["q"]; ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+	}
+	return q
+}
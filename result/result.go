@@ -2,7 +2,6 @@ package result
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
 	"net/http"
 	"sync"
@@ -22,6 +21,9 @@ var bufPool = sync.Pool{
 type Result interface {
 	// JSON outputs json result
 	JSON(w http.ResponseWriter, r *http.Request)
+
+	// Render outputs the result in the format negotiated from the Accept header (see Register)
+	Render(w http.ResponseWriter, r *http.Request)
 }
 
 type result struct {
@@ -31,6 +33,20 @@ type result struct {
 }
 
 func (ret *result) JSON(w http.ResponseWriter, r *http.Request) {
+	ret.write(w, r, MIMEJSON, jsonEncoder)
+}
+
+func (ret *result) Render(w http.ResponseWriter, r *http.Request) {
+	for _, v := range negotiate(r.Header.Get("Accept")) {
+		if v.enc.Accept(ret.Data) {
+			ret.write(w, r, v.contentType, v.enc)
+			return
+		}
+	}
+	ret.write(w, r, MIMEJSON, jsonEncoder)
+}
+
+func (ret *result) write(w http.ResponseWriter, r *http.Request, contentType string, enc Encoder) {
 	buf := bufPool.Get().(*bytes.Buffer)
 	buf.Reset()
 	defer func() {
@@ -41,14 +57,12 @@ func (ret *result) JSON(w http.ResponseWriter, r *http.Request) {
 		bufPool.Put(buf)
 	}()
 
-	enc := json.NewEncoder(buf)
-	enc.SetEscapeHTML(false)
-	if err := enc.Encode(ret); err != nil {
+	if err := enc.Encode(buf, w.Header(), &Body{Code: ret.Code, Msg: ret.Msg, Data: ret.Data}); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", contentType)
 	w.WriteHeader(http.StatusOK)
 	w.Write(buf.Bytes())
 }
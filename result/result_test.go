@@ -0,0 +1,98 @@
+package result
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/noble-gase/ne/codekit"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func render(ret Result, accept string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if len(accept) != 0 {
+		r.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	ret.Render(w, r)
+	return w
+}
+
+type demo struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestRender(t *testing.T) {
+	// 默认 JSON
+	w := render(OK(&demo{Name: "hello"}), "")
+	assert.Equal(t, MIMEJSON, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"code":0,"msg":"OK","data":{"name":"hello"}}`, w.Body.String())
+
+	// 浏览器优先 JSON
+	w = render(OK(&demo{Name: "hello"}), "text/html, application/xml;q=0.9, */*;q=0.8")
+	assert.Equal(t, MIMEJSON, w.Header().Get("Content-Type"))
+
+	w = render(OK(&demo{Name: "hello"}), "application/xml, */*;q=0.8")
+	assert.Equal(t, MIMEXML, w.Header().Get("Content-Type"))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<result><code>0</code><msg>OK</msg><data><name>hello</name></data></result>`, w.Body.String())
+
+	// XML 无法编码 map，回退到 JSON
+	w = render(OK(map[string]any{"name": "hello"}), "application/xml, */*;q=0.8")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, MIMEJSON, w.Header().Get("Content-Type"))
+	w = render(OK([]any{&demo{Name: "a"}, map[string]int{"b": 1}}), "application/xml, */*;q=0.8")
+	assert.Equal(t, MIMEJSON, w.Header().Get("Content-Type"))
+
+	// 非 proto.Message 回退到下一个可接受的格式
+	w = render(OK(&demo{Name: "hello"}), "application/x-protobuf, application/json;q=0.5")
+	assert.Equal(t, MIMEJSON, w.Header().Get("Content-Type"))
+
+	w = render(Err(errors.New("oops")), "application/x-protobuf")
+	assert.Equal(t, MIMEProtobuf, w.Header().Get("Content-Type"))
+	assert.Equal(t, "-1", w.Header().Get(HeaderCode))
+	assert.Equal(t, 0, w.Body.Len())
+}
+
+func TestRenderProto(t *testing.T) {
+	msg := wrapperspb.String("hello")
+
+	w := render(OK(msg), "application/x-protobuf")
+	assert.Equal(t, MIMEProtobuf, w.Header().Get("Content-Type"))
+	assert.Equal(t, "0", w.Header().Get(HeaderCode))
+	ret := new(wrapperspb.StringValue)
+	assert.Nil(t, proto.Unmarshal(w.Body.Bytes(), ret))
+	assert.Equal(t, "hello", ret.GetValue())
+
+	// protojson 编码
+	w = render(OK(msg), "application/json")
+	assert.JSONEq(t, `{"code":0,"msg":"OK","data":"hello"}`, w.Body.String())
+}
+
+func TestRegister(t *testing.T) {
+	// 恢复全局注册表，避免影响其它测试
+	saved := slices.Clone(registry)
+	t.Cleanup(func() {
+		regMutex.Lock()
+		registry = saved
+		regMutex.Unlock()
+	})
+
+	Register(EncoderFunc(func(buf *bytes.Buffer, header http.Header, body *Body) error {
+		buf.WriteString(body.Msg)
+		return nil
+	}), "text/plain")
+
+	w := render(New(codekit.OK), "text/plain")
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Equal(t, "OK", w.Body.String())
+
+	// 通配时按注册顺序
+	w = render(New(codekit.OK), "text/*")
+	assert.Equal(t, MIMEJSON, w.Header().Get("Content-Type"))
+}
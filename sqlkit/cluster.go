@@ -0,0 +1,353 @@
+package sqlkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-jet/jet/v2/qrm"
+)
+
+// Balance 从库负载均衡策略
+type Balance string
+
+const (
+	RoundRobin Balance = "round_robin" // 轮询
+	LeastConn  Balance = "least_conn"  // 最少连接
+)
+
+// ClusterConfig 读写分离配置
+type ClusterConfig struct {
+	// Primary 主库
+	Primary Config `json:"primary" mapstructure:"primary"`
+	// Replicas 从库
+	Replicas []Config `json:"replicas" mapstructure:"replicas"`
+	// Balance 从库负载均衡策略，默认：round_robin
+	Balance Balance `json:"balance" mapstructure:"balance"`
+	// HealthCheck 从库健康检查间隔（如："5s"，纯数字的单位为秒），默认：5s
+	HealthCheck Duration `json:"health_check" mapstructure:"health_check"`
+	// MaxLag 从库最大复制延迟（如："10s"，纯数字的单位为秒），超过后不再路由读请求；0 表示不检查
+	MaxLag Duration `json:"max_lag" mapstructure:"max_lag"`
+}
+
+type replica struct {
+	name    string
+	driver  string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+// Cluster 读写分离的数据库集群，实现了 qrm.DB，可直接用于 curd 方法
+//
+//	[事务] ctx 中存在主库事务（见 Transaction）时，读写均在该事务中执行
+//	[主库] 写操作、事务、带锁读（FOR UPDATE 等）、WithPrimary/ReadYourWrites 标记的读
+//	[从库] 其它 SELECT 查询；无健康从库时回落到主库
+type Cluster struct {
+	primary  *sql.DB
+	replicas []*replica
+	balance  Balance
+	maxLag   time.Duration
+	next     atomic.Uint64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ qrm.DB = (*Cluster)(nil)
+
+// Primary 返回主库，用于 Transaction 等
+func (c *Cluster) Primary() *sql.DB {
+	return c.primary
+}
+
+// Replica 返回一个健康的从库，无健康从库时返回主库
+func (c *Cluster) Replica() *sql.DB {
+	healthy := make([]*replica, 0, len(c.replicas))
+	for _, r := range c.replicas {
+		if r.healthy.Load() {
+			healthy = append(healthy, r)
+		}
+	}
+	if len(healthy) == 0 {
+		return c.primary
+	}
+
+	if c.balance == LeastConn {
+		db := healthy[0].db
+		inUse := db.Stats().InUse
+		for _, r := range healthy[1:] {
+			if n := r.db.Stats().InUse; n < inUse {
+				db, inUse = r.db, n
+			}
+		}
+		return db
+	}
+
+	n := c.next.Add(1)
+	return healthy[(n-1)%uint64(len(healthy))].db
+}
+
+func (c *Cluster) Exec(query string, args ...any) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+func (c *Cluster) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	markWritten(ctx)
+	return c.executor(ctx).ExecContext(ctx, query, args...)
+}
+
+func (c *Cluster) Query(query string, args ...any) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+func (c *Cluster) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	// ctx 中存在主库事务时，读写均在事务中执行
+	if tx, ok := c.executor(ctx).(*sql.Tx); ok {
+		if !isReadOnly(query) {
+			markWritten(ctx)
+		}
+		return tx.QueryContext(ctx, query, args...)
+	}
+	if !isReadOnly(query) {
+		// 如：INSERT ... RETURNING
+		markWritten(ctx)
+		return c.primary.QueryContext(ctx, query, args...)
+	}
+	if usePrimary(ctx) {
+		return c.primary.QueryContext(ctx, query, args...)
+	}
+	return c.Replica().QueryContext(ctx, query, args...)
+}
+
+// executor 返回 ctx 中主库的事务（见 Executor），不存在时返回主库
+func (c *Cluster) executor(ctx context.Context) qrm.DB {
+	return Executor(ctx, c.primary)
+}
+
+// Close 停止健康检查并关闭所有连接
+func (c *Cluster) Close() error {
+	if c.cancel != nil {
+		c.cancel()
+		c.wg.Wait()
+	}
+
+	var errs []error
+	if err := c.primary.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close primary: %w", err))
+	}
+	for _, r := range c.replicas {
+		if err := r.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", r.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Cluster) healthCheck(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, r := range c.replicas {
+			err := c.check(ctx, r)
+			if ok := err == nil; r.healthy.Swap(ok) != ok {
+				if ok {
+					slog.LogAttrs(ctx, slog.LevelInfo, "[sqlkit:Cluster] replica recovered", slog.String("replica", r.name))
+				} else {
+					slog.LogAttrs(ctx, slog.LevelWarn, "[sqlkit:Cluster] replica unhealthy", slog.String("replica", r.name), slog.Any("error", err))
+				}
+			}
+		}
+	}
+}
+
+func (c *Cluster) check(ctx context.Context, r *replica) error {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if err := r.db.PingContext(ctx); err != nil {
+		return err
+	}
+	if c.maxLag <= 0 {
+		return nil
+	}
+
+	lag, err := replicationLag(ctx, r.driver, r.db)
+	if err != nil {
+		return fmt.Errorf("replication lag: %w", err)
+	}
+	if lag > c.maxLag {
+		return fmt.Errorf("replication lag %s exceeds %s", lag, c.maxLag)
+	}
+	return nil
+}
+
+// replicationLag 返回从库的复制延迟
+func replicationLag(ctx context.Context, driver string, db *sql.DB) (time.Duration, error) {
+	switch driver {
+	case "pgx", "postgres":
+		var sec float64
+		err := db.QueryRowContext(ctx, "SELECT COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)").Scan(&sec)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(sec * float64(time.Second)), nil
+	case "mysql":
+		rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+		if err != nil {
+			return 0, err
+		}
+		defer rows.Close()
+
+		cols, err := rows.Columns()
+		if err != nil {
+			return 0, err
+		}
+		if !rows.Next() {
+			// 非从库
+			return 0, rows.Err()
+		}
+
+		values := make([]sql.RawBytes, len(cols))
+		dest := make([]any, len(cols))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err = rows.Scan(dest...); err != nil {
+			return 0, err
+		}
+		for i, col := range cols {
+			if col == "Seconds_Behind_Source" || col == "Seconds_Behind_Master" {
+				if values[i] == nil {
+					return 0, errors.New("replication is not running")
+				}
+				sec, err := strconv.ParseInt(string(values[i]), 10, 64)
+				if err != nil {
+					return 0, err
+				}
+				return time.Duration(sec) * time.Second, nil
+			}
+		}
+		return 0, nil
+	}
+	return 0, nil
+}
+
+// NewCluster 返回一个读写分离的数据库集群
+func NewCluster(cfg *ClusterConfig) (*Cluster, error) {
+	interval, err := cfg.HealthCheck.Value()
+	if err != nil {
+		return nil, fmt.Errorf("health_check: %w", err)
+	}
+	maxLag, err := cfg.MaxLag.Value()
+	if err != nil {
+		return nil, fmt.Errorf("max_lag: %w", err)
+	}
+
+	primary, err := NewDB(&cfg.Primary)
+	if err != nil {
+		return nil, fmt.Errorf("primary: %w", err)
+	}
+
+	c := &Cluster{
+		primary:  primary,
+		replicas: make([]*replica, 0, len(cfg.Replicas)),
+		balance:  cfg.Balance,
+		maxLag:   maxLag,
+	}
+	for i := range cfg.Replicas {
+		db, err := NewDB(&cfg.Replicas[i])
+		if err != nil {
+			_ = c.Close()
+			return nil, fmt.Errorf("replica-%d: %w", i, err)
+		}
+
+		r := &replica{
+			name:   "replica-" + strconv.Itoa(i),
+			driver: cfg.Replicas[i].Driver,
+			db:     db,
+		}
+		r.healthy.Store(true)
+		c.replicas = append(c.replicas, r)
+	}
+
+	if len(c.replicas) != 0 {
+		if interval <= 0 {
+			interval = 5 * time.Second
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		c.cancel = cancel
+
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.healthCheck(ctx, interval)
+		}()
+	}
+	return c, nil
+}
+
+type ctxPrimaryKey struct{}
+
+type sticky struct {
+	written atomic.Bool
+}
+
+// WithPrimary 标记 ctx 中的读操作走主库
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxPrimaryKey{}, true)
+}
+
+// ReadYourWrites 标记 ctx 在发生写操作后，后续读操作走主库（读己之写）
+func ReadYourWrites(ctx context.Context) context.Context {
+	if ctx.Value(ctxPrimaryKey{}) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxPrimaryKey{}, &sticky{})
+}
+
+func usePrimary(ctx context.Context) bool {
+	switch v := ctx.Value(ctxPrimaryKey{}).(type) {
+	case bool:
+		return v
+	case *sticky:
+		return v.written.Load()
+	}
+	return false
+}
+
+func markWritten(ctx context.Context) {
+	if v, ok := ctx.Value(ctxPrimaryKey{}).(*sticky); ok {
+		v.written.Store(true)
+	}
+}
+
+// isReadOnly 判断是否为可路由到从库的只读查询
+func isReadOnly(query string) bool {
+	s := strings.TrimLeft(query, " \t\r\n(")
+	if len(s) < 6 || !strings.EqualFold(s[:6], "SELECT") {
+		return false
+	}
+
+	s = strings.ToUpper(s)
+	for _, v := range []string{"FOR UPDATE", "FOR SHARE", "LOCK IN SHARE MODE", "FOR NO KEY UPDATE", "FOR KEY SHARE"} {
+		if strings.Contains(s, v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package sqlkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReadOnly(t *testing.T) {
+	assert.True(t, isReadOnly("SELECT id FROM demo"))
+	assert.True(t, isReadOnly("\n(select id FROM demo) UNION (SELECT id FROM demo)"))
+	assert.False(t, isReadOnly("SELECT id FROM demo FOR UPDATE"))
+	assert.False(t, isReadOnly("INSERT INTO demo (name) VALUES ('hello') RETURNING id"))
+	assert.False(t, isReadOnly("WITH t AS (DELETE FROM demo RETURNING id) SELECT * FROM t"))
+}
+
+func TestCluster(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := NewCluster(&ClusterConfig{
+		Primary: Config{Driver: "sqlite3", DSN: "file:" + filepath.Join(dir, "primary.db")},
+		Replicas: []Config{
+			{Driver: "sqlite3", DSN: "file:" + filepath.Join(dir, "replica.db")},
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	_, err = c.Primary().ExecContext(ctx, "CREATE TABLE demo (name TEXT)")
+	assert.Nil(t, err)
+	_, err = c.Replica().ExecContext(ctx, "CREATE TABLE demo (name TEXT)")
+	assert.Nil(t, err)
+
+	ctx = ReadYourWrites(ctx)
+
+	count := func() int {
+		var n int
+		rows, err := c.QueryContext(ctx, "SELECT COUNT(*) FROM demo")
+		if assert.Nil(t, err) {
+			defer rows.Close()
+			rows.Next()
+			assert.Nil(t, rows.Scan(&n))
+		}
+		return n
+	}
+	// 读从库
+	assert.Equal(t, 0, count())
+
+	_, err = c.ExecContext(ctx, "INSERT INTO demo (name) VALUES ('hello')")
+	assert.Nil(t, err)
+	// 写后读主库
+	assert.Equal(t, 1, count())
+
+	// 主库事务中通过 Cluster 执行的读写加入该事务
+	err = Transaction(context.Background(), c.Primary(), func(ctx context.Context, tx *sql.Tx) error {
+		_, err := c.ExecContext(ctx, "INSERT INTO demo (name) VALUES ('world')")
+		assert.Nil(t, err)
+
+		// 读取事务内未提交的数据
+		var n int
+		rows, err := c.QueryContext(ctx, "SELECT COUNT(*) FROM demo")
+		if assert.Nil(t, err) {
+			defer rows.Close()
+			rows.Next()
+			assert.Nil(t, rows.Scan(&n))
+		}
+		assert.Equal(t, 2, n)
+		return errors.New("rollback")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, count())
+}
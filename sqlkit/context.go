@@ -0,0 +1,30 @@
+package sqlkit
+
+import (
+	"context"
+
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// WithOperator 在 ctx 中设置操作人，用于填充审计字段（CreatedBy/UpdatedBy）
+func WithOperator(ctx context.Context, operator any) context.Context {
+	return internal.WithOperator(ctx, operator)
+}
+
+// Operator 返回 ctx 中的操作人
+func Operator(ctx context.Context) (any, bool) {
+	return internal.Operator(ctx)
+}
+
+type tenantKey struct{}
+
+// WithTenant 在 ctx 中设置租户ID，用于 ShardRouter 路由
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// Tenant 返回 ctx 中的租户ID
+func Tenant(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(tenantKey{}).(string)
+	return v, ok && len(v) != 0
+}
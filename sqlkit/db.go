@@ -2,12 +2,18 @@ package sqlkit
 
 import (
 	"database/sql"
-	"time"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/mattn/go-sqlite3"
-	"github.com/noble-gase/ne/sqlkit/internal"
+	"github.com/noble-gase/ne/helper"
 )
 
 // Config 数据库初始化配置
@@ -17,12 +23,27 @@ type Config struct {
 	//  [-PgSQL] pgx
 	//  [SQLite] sqlite3
 	Driver string `json:"driver" mapstructure:"driver"`
-	// DSN 数据源名称
+	// DSN 数据源名称，可与下方的结构化字段同时使用（结构化字段优先）
 	//
 	//  [-MySQL] <username>:<password>@tcp(<host>:3306)/<db>?timeout=10s&charset=utf8mb4&parseTime=True&loc=Local
 	//  [-PgSQL] postgres://<username>:<password>@<host>:5432/<dbname>
 	//  [SQLite] file::memory:?cache=shared || file:</path/test.db>
 	DSN string `json:"dsn" mapstructure:"dsn"`
+	// Host 主机（SQLite 忽略）
+	Host string `json:"host" mapstructure:"host"`
+	// Port 端口（SQLite 忽略）
+	Port int `json:"port" mapstructure:"port"`
+	// User 用户名（SQLite 忽略）
+	User string `json:"user" mapstructure:"user"`
+	// Password 密码（SQLite 忽略），支持引用：
+	//
+	//	env:DB_PASSWORD         从环境变量读取
+	//	file:/run/secrets/db    从文件读取（去除首尾空白）
+	Password string `json:"password" mapstructure:"password"`
+	// Database 数据库名称（SQLite 为文件路径）
+	Database string `json:"database" mapstructure:"database"`
+	// Params 连接参数，如：{"charset": "utf8mb4", "parseTime": "true"}、{"sslmode": "disable"}
+	Params map[string]string `json:"params" mapstructure:"params"`
 	// Options 数据库连接池选项
 	Options Options `json:"options" mapstructure:"options"`
 }
@@ -32,15 +53,192 @@ type Options struct {
 	MaxOpenConns int `json:"max_open_conns" mapstructure:"max_open_conns"`
 	// MaxIdleConns 连接池最大闲置连接数
 	MaxIdleConns int `json:"max_idle_conns" mapstructure:"max_idle_conns"`
-	// ConnMaxIdleTime 连接最大闲置时间（单位：秒）
-	ConnMaxIdleTime int `json:"conn_max_idle_time" mapstructure:"conn_max_idle_time"`
-	// ConnMaxLifetime 连接的最大生命时长（单位：秒）
-	ConnMaxLifetime int `json:"conn_max_lifetime" mapstructure:"conn_max_lifetime"`
+	// ConnMaxIdleTime 连接最大闲置时间（如："30m"，纯数字的单位为秒）
+	ConnMaxIdleTime Duration `json:"conn_max_idle_time" mapstructure:"conn_max_idle_time"`
+	// ConnMaxLifetime 连接的最大生命时长（如："1h"，纯数字的单位为秒）
+	ConnMaxLifetime Duration `json:"conn_max_lifetime" mapstructure:"conn_max_lifetime"`
+}
+
+// Duration 时长配置，见 helper.Duration
+type Duration = helper.Duration
+
+// BuildDSN 合并 DSN 和结构化字段，返回最终的数据源名称
+func (c *Config) BuildDSN() (string, error) {
+	password, err := secret(c.Password)
+	if err != nil {
+		return "", fmt.Errorf("password: %w", err)
+	}
+
+	switch c.Driver {
+	case "mysql":
+		return c.mysqlDSN(password)
+	case "pgx", "postgres":
+		return c.pgsqlDSN(password)
+	case "sqlite3", "sqlite":
+		return c.sqliteDSN()
+	}
+	return c.DSN, nil
+}
+
+func (c *Config) address(port int) string {
+	if c.Port != 0 {
+		port = c.Port
+	}
+	return net.JoinHostPort(c.Host, strconv.Itoa(port))
+}
+
+func (c *Config) mysqlDSN(password string) (string, error) {
+	cfg := mysql.NewConfig()
+	if len(c.DSN) != 0 {
+		v, err := mysql.ParseDSN(c.DSN)
+		if err != nil {
+			return "", err
+		}
+		cfg = v
+	}
+
+	if len(c.Host) != 0 {
+		cfg.Net = "tcp"
+		cfg.Addr = c.address(3306)
+	}
+	if len(c.User) != 0 {
+		cfg.User = c.User
+	}
+	if len(password) != 0 {
+		cfg.Passwd = password
+	}
+	if len(c.Database) != 0 {
+		cfg.DBName = c.Database
+	}
+
+	dsn := cfg.FormatDSN()
+	if len(c.Params) == 0 {
+		return dsn, nil
+	}
+
+	// 通过重新解析使 parseTime、loc 等参数生效
+	query := url.Values{}
+	for k, v := range c.Params {
+		query.Set(k, v)
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	cfg, err := mysql.ParseDSN(dsn + sep + query.Encode())
+	if err != nil {
+		return "", err
+	}
+	return cfg.FormatDSN(), nil
+}
+
+func (c *Config) pgsqlDSN(password string) (string, error) {
+	dsn := c.DSN
+	if len(dsn) == 0 {
+		dsn = "postgres://"
+	}
+	if !strings.Contains(dsn, "://") {
+		// keyword/value 格式
+		if len(c.Host) != 0 || len(c.User) != 0 || len(password) != 0 || len(c.Database) != 0 || len(c.Params) != 0 {
+			return "", errors.New("structured fields require a URL DSN (postgres://...)")
+		}
+		return dsn, nil
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+
+	if len(c.Host) != 0 {
+		u.Host = c.address(5432)
+	}
+	if len(c.User) != 0 || len(password) != 0 {
+		user := c.User
+		if len(user) == 0 && u.User != nil {
+			user = u.User.Username()
+		}
+		if len(password) == 0 && u.User != nil {
+			password, _ = u.User.Password()
+		}
+		if len(password) != 0 {
+			u.User = url.UserPassword(user, password)
+		} else {
+			u.User = url.User(user)
+		}
+	}
+	if len(c.Database) != 0 {
+		u.Path = "/" + c.Database
+	}
+	if len(c.Params) != 0 {
+		query := u.Query()
+		for k, v := range c.Params {
+			query.Set(k, v)
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String(), nil
+}
+
+func (c *Config) sqliteDSN() (string, error) {
+	dsn := c.DSN
+	if len(c.Database) != 0 {
+		dsn = "file:" + c.Database
+		if _, query, ok := strings.Cut(c.DSN, "?"); ok {
+			dsn += "?" + query
+		}
+	}
+	if len(c.Params) == 0 {
+		return dsn, nil
+	}
+
+	path, rawQuery, _ := strings.Cut(dsn, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", err
+	}
+	for k, v := range c.Params {
+		query.Set(k, v)
+	}
+	return path + "?" + query.Encode(), nil
+}
+
+// secret 解析密码引用：env:NAME | file:/path，否则原样返回
+func secret(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, "env:"):
+		name := strings.TrimPrefix(s, "env:")
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("env %s not set", name)
+		}
+		return v, nil
+	case strings.HasPrefix(s, "file:"):
+		b, err := os.ReadFile(strings.TrimPrefix(s, "file:"))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	return s, nil
 }
 
 // NewDB returns a new sql.DB
 func NewDB(cfg *Config) (*sql.DB, error) {
-	db, err := sql.Open(cfg.Driver, cfg.DSN)
+	dsn, err := cfg.BuildDSN()
+	if err != nil {
+		return nil, err
+	}
+	idleTime, err := cfg.Options.ConnMaxIdleTime.Value()
+	if err != nil {
+		return nil, fmt.Errorf("conn_max_idle_time: %w", err)
+	}
+	lifetime, err := cfg.Options.ConnMaxLifetime.Value()
+	if err != nil {
+		return nil, fmt.Errorf("conn_max_lifetime: %w", err)
+	}
+
+	db, err := sql.Open(cfg.Driver, dsn)
 	if err != nil {
 		return nil, err
 	}
@@ -51,12 +249,8 @@ func NewDB(cfg *Config) (*sql.DB, error) {
 
 	db.SetMaxOpenConns(cfg.Options.MaxOpenConns)
 	db.SetMaxIdleConns(cfg.Options.MaxIdleConns)
-	db.SetConnMaxIdleTime(time.Duration(cfg.Options.ConnMaxIdleTime) * time.Second)
-	db.SetConnMaxLifetime(time.Duration(cfg.Options.ConnMaxLifetime) * time.Second)
+	db.SetConnMaxIdleTime(idleTime)
+	db.SetConnMaxLifetime(lifetime)
 
 	return db, nil
 }
-
-func SetLogger(fn internal.LogFunc) {
-	internal.Logger = fn
-}
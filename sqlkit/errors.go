@@ -0,0 +1,233 @@
+package sqlkit
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"github.com/noble-gase/ne/codekit"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+func init() {
+	// 使 codekit.FromError 可直接匹配驱动错误，如：codekit.Map(sqlkit.UniqueViolation, ...)
+	codekit.Classifier(Classify)
+}
+
+// Category 数据库错误类别，可作为 errors.Is 的目标
+//
+//	if errors.Is(sqlkit.Classify(err), sqlkit.UniqueViolation) {
+//		// 记录已存在
+//	}
+type Category string
+
+const (
+	UniqueViolation      Category = "unique_violation"      // 唯一约束冲突
+	ForeignKeyViolation  Category = "foreign_key_violation" // 外键约束冲突
+	NotNullViolation     Category = "not_null_violation"    // 非空约束冲突
+	CheckViolation       Category = "check_violation"       // CHECK约束冲突
+	Deadlock             Category = "deadlock"              // 死锁
+	SerializationFailure Category = "serialization_failure" // 序列化失败
+	LockTimeout          Category = "lock_timeout"          // 锁等待超时
+	ConnectionLost       Category = "connection_lost"       // 连接断开
+	QueryCanceled        Category = "query_canceled"        // 查询被取消或超时
+)
+
+func (c Category) Error() string {
+	return "sqlkit: " + string(c)
+}
+
+// Error 分类后的数据库错误
+type Error struct {
+	// Category 错误类别
+	Category Category
+	// Constraint 违反的约束名称（如：唯一索引名），无法获取时为空
+	Constraint string
+	// Err 原始错误
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is 支持 errors.Is(err, sqlkit.UniqueViolation) 等判断
+func (e *Error) Is(target error) bool {
+	c, ok := target.(Category)
+	return ok && c == e.Category
+}
+
+// Classify 识别 MySQL、PgSQL、SQLite 驱动错误并分类，返回 *Error；无法识别时原样返回 err
+//
+//	err = sqlkit.Classify(err)
+//
+//	var dbErr *sqlkit.Error
+//	if errors.As(err, &dbErr) && dbErr.Category == sqlkit.UniqueViolation {
+//		fmt.Println(dbErr.Constraint)
+//	}
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	var (
+		category   Category
+		constraint string
+	)
+
+	var (
+		myErr   *mysql.MySQLError
+		pgErr   *pgconn.PgError
+		liteErr sqlite3.Error
+	)
+	switch {
+	case errors.As(err, &myErr):
+		category, constraint = classifyMySQL(myErr)
+	case errors.As(err, &pgErr):
+		category, constraint = classifyPgSQL(pgErr)
+	case errors.As(err, &liteErr):
+		category, constraint = classifySQLite(liteErr)
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		category = QueryCanceled
+	case errors.Is(err, driver.ErrBadConn), errors.Is(err, mysql.ErrInvalidConn), isConnectError(err):
+		category = ConnectionLost
+	}
+	if len(category) == 0 {
+		return err
+	}
+	return &Error{
+		Category:   category,
+		Constraint: constraint,
+		Err:        err,
+	}
+}
+
+// classifyMySQL 参考：https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+func classifyMySQL(err *mysql.MySQLError) (Category, string) {
+	switch err.Number {
+	case 1062, 1586: // ER_DUP_ENTRY, ER_DUP_ENTRY_WITH_KEY_NAME
+		// Duplicate entry 'xxx' for key 'uniq_name'
+		return UniqueViolation, quoted(err.Message, "for key ")
+	case 1216, 1217, 1451, 1452: // ER_NO_REFERENCED_ROW, ER_ROW_IS_REFERENCED
+		// ... CONSTRAINT `fk_name` FOREIGN KEY ...
+		return ForeignKeyViolation, quoted(err.Message, "CONSTRAINT ")
+	case 1048: // ER_BAD_NULL_ERROR
+		return NotNullViolation, quoted(err.Message, "Column ")
+	case 3819: // ER_CHECK_CONSTRAINT_VIOLATED
+		return CheckViolation, quoted(err.Message, "Check constraint ")
+	case 1213: // ER_LOCK_DEADLOCK
+		return Deadlock, ""
+	case 1205, 3572: // ER_LOCK_WAIT_TIMEOUT, ER_LOCK_NOWAIT
+		return LockTimeout, ""
+	case 1317, 3024: // ER_QUERY_INTERRUPTED, ER_QUERY_TIMEOUT
+		return QueryCanceled, ""
+	case 1053, 1927, 2006, 2013: // ER_SERVER_SHUTDOWN, ER_CONNECTION_KILLED, CR_SERVER_GONE_ERROR, CR_SERVER_LOST
+		return ConnectionLost, ""
+	}
+	return "", ""
+}
+
+// classifyPgSQL 参考：https://www.postgresql.org/docs/current/errcodes-appendix.html
+func classifyPgSQL(err *pgconn.PgError) (Category, string) {
+	switch err.Code {
+	case "23505":
+		return UniqueViolation, err.ConstraintName
+	case "23503":
+		return ForeignKeyViolation, err.ConstraintName
+	case "23502":
+		return NotNullViolation, err.ColumnName
+	case "23514":
+		return CheckViolation, err.ConstraintName
+	case "40P01":
+		return Deadlock, ""
+	case "40001":
+		return SerializationFailure, ""
+	case "55P03":
+		return LockTimeout, ""
+	case "57014":
+		return QueryCanceled, ""
+	case "57P01", "57P02", "57P03":
+		return ConnectionLost, ""
+	}
+	if strings.HasPrefix(err.Code, "08") {
+		return ConnectionLost, ""
+	}
+	return "", ""
+}
+
+// classifySQLite 参考：https://www.sqlite.org/rescode.html
+func classifySQLite(err sqlite3.Error) (Category, string) {
+	// UNIQUE constraint failed: demo.name
+	constraint := ""
+	if _, s, ok := strings.Cut(err.Error(), "constraint failed: "); ok {
+		constraint = s
+	}
+
+	switch err.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return UniqueViolation, constraint
+	case sqlite3.ErrConstraintForeignKey:
+		return ForeignKeyViolation, constraint
+	case sqlite3.ErrConstraintNotNull:
+		return NotNullViolation, constraint
+	case sqlite3.ErrConstraintCheck:
+		return CheckViolation, constraint
+	case sqlite3.ErrBusySnapshot:
+		return SerializationFailure, ""
+	}
+
+	switch err.Code {
+	case sqlite3.ErrBusy, sqlite3.ErrLocked:
+		return LockTimeout, ""
+	case sqlite3.ErrInterrupt:
+		return QueryCanceled, ""
+	}
+	return "", ""
+}
+
+func isConnectError(err error) bool {
+	var connErr *pgconn.ConnectError
+	return errors.As(err, &connErr)
+}
+
+// quoted 返回 prefix 之后第一个被引号（' 或 `）包围的内容
+func quoted(msg, prefix string) string {
+	_, s, ok := strings.Cut(msg, prefix)
+	if !ok || len(s) == 0 {
+		return ""
+	}
+	q := s[0]
+	if q != '\'' && q != '`' {
+		return ""
+	}
+	if i := strings.IndexByte(s[1:], q); i >= 0 {
+		return s[1 : i+1]
+	}
+	return ""
+}
+
+// IsRetryable 判断是否为可重试的事务错误（死锁、序列化失败、锁等待超时）
+//
+//	[-MySQL] 1213 死锁；1205 锁等待超时
+//	[-PgSQL] 40001 序列化失败；40P01 死锁；55P03 锁不可用
+//	[SQLite] SQLITE_BUSY；SQLITE_LOCKED
+func IsRetryable(err error) bool {
+	err = Classify(err)
+	return errors.Is(err, Deadlock) || errors.Is(err, SerializationFailure) || errors.Is(err, LockTimeout)
+}
+
+// ErrStaleVersion 乐观锁版本冲突，见 mysql/pgsql/sqlite 的 Convention.Update
+var ErrStaleVersion = internal.ErrStaleVersion
@@ -0,0 +1,75 @@
+package sqlkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"github.com/noble-gase/ne/codekit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	assert.False(t, IsRetryable(nil))
+	assert.False(t, IsRetryable(errors.New("oh no")))
+	assert.True(t, IsRetryable(fmt.Errorf("commit: %w", &mysql.MySQLError{Number: 1213})))
+	assert.False(t, IsRetryable(&mysql.MySQLError{Number: 1062}))
+	assert.True(t, IsRetryable(&pgconn.PgError{Code: "40001"}))
+	assert.True(t, IsRetryable(sqlite3.Error{Code: sqlite3.ErrBusy}))
+}
+
+func TestClassify(t *testing.T) {
+	assert.Nil(t, Classify(nil))
+
+	err := errors.New("oh no")
+	assert.Equal(t, err, Classify(err))
+
+	err = Classify(fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'hello' for key 'demo.uniq_name'"}))
+	assert.ErrorIs(t, err, UniqueViolation)
+	assert.NotErrorIs(t, err, Deadlock)
+	var dbErr *Error
+	if assert.ErrorAs(t, err, &dbErr) {
+		assert.Equal(t, "demo.uniq_name", dbErr.Constraint)
+	}
+	assert.Equal(t, err, Classify(err))
+
+	err = Classify(&mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row: a foreign key constraint fails (`db`.`child`, CONSTRAINT `fk_parent` FOREIGN KEY (`pid`) REFERENCES `parent` (`id`))"})
+	if assert.ErrorAs(t, err, &dbErr) {
+		assert.Equal(t, ForeignKeyViolation, dbErr.Category)
+		assert.Equal(t, "fk_parent", dbErr.Constraint)
+	}
+
+	err = Classify(&pgconn.PgError{Code: "23505", ConstraintName: "uniq_name"})
+	if assert.ErrorAs(t, err, &dbErr) {
+		assert.Equal(t, UniqueViolation, dbErr.Category)
+		assert.Equal(t, "uniq_name", dbErr.Constraint)
+	}
+	assert.ErrorIs(t, Classify(&pgconn.PgError{Code: "23502"}), NotNullViolation)
+	assert.ErrorIs(t, Classify(&pgconn.PgError{Code: "40001"}), SerializationFailure)
+	assert.ErrorIs(t, Classify(&pgconn.PgError{Code: "57014"}), QueryCanceled)
+	assert.ErrorIs(t, Classify(&pgconn.PgError{Code: "08006"}), ConnectionLost)
+
+	assert.ErrorIs(t, Classify(sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintCheck}), CheckViolation)
+	assert.ErrorIs(t, Classify(sqlite3.Error{Code: sqlite3.ErrLocked}), LockTimeout)
+	assert.ErrorIs(t, Classify(context.Canceled), QueryCanceled)
+	assert.ErrorIs(t, Classify(mysql.ErrInvalidConn), ConnectionLost)
+}
+
+func TestFromError(t *testing.T) {
+	exists := codekit.New(10001, "Record Already Exists")
+	codekit.Map(UniqueViolation, exists)
+
+	// 驱动原始错误经 Classify 后匹配
+	c := codekit.FromError(fmt.Errorf("insert: %w", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'hello' for key 'demo.uniq_name'"}))
+	assert.Equal(t, exists.Value(), c.Value())
+
+	c = codekit.FromError(&pgconn.PgError{Code: "23505", ConstraintName: "uniq_name"})
+	assert.Equal(t, exists.Value(), c.Value())
+
+	c = codekit.FromError(&pgconn.PgError{Code: "23502"})
+	assert.Equal(t, codekit.Err.Value(), c.Value())
+}
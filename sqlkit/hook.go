@@ -0,0 +1,79 @@
+package sqlkit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+)
+
+type hooksKey struct{}
+
+// txHooks 事务提交/回滚后的回调
+type txHooks struct {
+	parent   *txHooks
+	commit   []func(ctx context.Context)
+	rollback []func(ctx context.Context)
+}
+
+func newTxHooks(ctx context.Context) *txHooks {
+	parent, _ := ctx.Value(hooksKey{}).(*txHooks)
+	return &txHooks{parent: parent}
+}
+
+// committed 事务提交后执行回调；
+// 嵌套事务（保存点）的回调转交外层事务，待外层事务提交/回滚后执行
+func (h *txHooks) committed(ctx context.Context, nested bool) {
+	if nested {
+		// 外层事务的结果未知，不可执行提交回调
+		if h.parent != nil {
+			h.parent.commit = append(h.parent.commit, h.commit...)
+			h.parent.rollback = append(h.parent.rollback, h.rollback...)
+		}
+		return
+	}
+	for _, fn := range h.commit {
+		runHook(ctx, fn)
+	}
+}
+
+// rolledBack 事务回滚后执行回调
+func (h *txHooks) rolledBack(ctx context.Context) {
+	for _, fn := range h.rollback {
+		runHook(ctx, fn)
+	}
+}
+
+func runHook(ctx context.Context, fn func(ctx context.Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.LogAttrs(ctx, slog.LevelError, "[sqlkit] transaction hook panic recovered", slog.String("error", fmt.Sprintf("%+v", r)), slog.String("stack", string(debug.Stack())))
+		}
+	}()
+	fn(ctx)
+}
+
+// AfterCommit 注册事务提交成功后执行的回调，如：清除缓存、发布事件等；
+// ctx 中不存在事务时立即执行
+//
+//	sqlkit.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+//		// ...
+//		sqlkit.AfterCommit(ctx, func(ctx context.Context) {
+//			_ = redkit.Del(ctx, uc, key)
+//		})
+//		return nil
+//	})
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if h, ok := ctx.Value(hooksKey{}).(*txHooks); ok {
+		h.commit = append(h.commit, fn)
+		return
+	}
+	runHook(ctx, fn)
+}
+
+// AfterRollback 注册事务回滚后执行的回调；ctx 中不存在事务时忽略
+func AfterRollback(ctx context.Context, fn func(ctx context.Context)) {
+	if h, ok := ctx.Value(hooksKey{}).(*txHooks); ok {
+		h.rollback = append(h.rollback, fn)
+	}
+}
@@ -0,0 +1,26 @@
+// Package builder 各方言共用的查询构建逻辑，由 mysql、pgsql、sqlite 包封装导出
+package builder
+
+import (
+	// jet 各方言的表达式、语句等类型及 Raw、AND 等函数均为同一实现的别名，与方言无关
+	jet "github.com/go-jet/jet/v2/mysql"
+)
+
+// Literal 方言相关的字面量构造（如：PostgreSQL 会附加类型转换），
+// 复数形式为各方言的 Exprs 系列函数，用于 IN 查询
+type Literal struct {
+	Int64  func(v int64) jet.IntegerExpression
+	Float  func(v float64) jet.FloatExpression
+	Bool   func(v bool) jet.BoolExpression
+	String func(v string) jet.StringExpression
+
+	Int64s  func(values []int64) []jet.Expression
+	Floats  func(values []float64) []jet.Expression
+	Bools   func(values []bool) []jet.Expression
+	Strings func(values []string) []jet.Expression
+}
+
+// Value 返回参数化的值（不附加类型转换）
+func Value(v any) jet.Expression {
+	return jet.Raw("#v", jet.RawArgs{"#v": v})
+}
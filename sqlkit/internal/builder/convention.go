@@ -0,0 +1,255 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	jet "github.com/go-jet/jet/v2/mysql"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// Convention 表约定：软删除、乐观锁、审计字段，列为 nil 表示不启用
+type Convention[Tb any, S Statements[Tb]] struct {
+	// Table 表
+	Table Tb
+	// DeletedAt 软删除时间列：Delete 时更新为当前时间，Scope 排除非 NULL 的记录
+	DeletedAt jet.Column
+	// Version 乐观锁版本列：Update 时校验 M 中的版本号并加1
+	Version jet.Column
+	// CreatedAt 创建时间列
+	CreatedAt jet.Column
+	// UpdatedAt 更新时间列
+	UpdatedAt jet.Column
+	// CreatedBy 创建人列（来自 sqlkit.WithOperator）
+	CreatedBy jet.Column
+	// UpdatedBy 更新人列（来自 sqlkit.WithOperator）
+	UpdatedBy jet.Column
+	// Now 返回审计时间的值，默认：time.Now()
+	Now func() any
+
+	stmts S
+}
+
+func (c *Convention[Tb, S]) now() any {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// Scope 返回排除已软删除记录的查询条件，cond 可为 nil
+func (c *Convention[Tb, S]) Scope(cond jet.BoolExpression) jet.BoolExpression {
+	if c.DeletedAt == nil {
+		if cond == nil {
+			return jet.RawBool("1 = 1")
+		}
+		return cond
+	}
+	if cond == nil {
+		return c.DeletedAt.IS_NULL()
+	}
+	return cond.AND(c.DeletedAt.IS_NULL())
+}
+
+// Insert 插入记录，自动填充创建/更新时间和操作人（m 中已设置的列不覆盖）；
+// 返回自增ID（PostgreSQL 不支持 LastInsertId，返回影响的行数）
+func (c *Convention[Tb, S]) Insert(ctx context.Context, db qrm.DB, m map[jet.Column]any) (int64, error) {
+	cols, vals, err := c.Inserting(ctx, m)
+	if err != nil {
+		return 0, err
+	}
+
+	ret, err := Exec(ctx, db, c.stmts.Insert(c.Table, cols, vals, nil))
+	if err != nil {
+		return 0, err
+	}
+	if c.stmts.Returning() {
+		return ret.RowsAffected()
+	}
+	id, _ := ret.LastInsertId()
+	return id, nil
+}
+
+// Update 更新记录，自动填充更新时间和操作人；
+// 启用乐观锁且 m 包含版本列时，以 m 中的值作为当前版本校验并加1，未更新到记录时返回 ErrStaleVersion
+func (c *Convention[Tb, S]) Update(ctx context.Context, db qrm.DB, m map[jet.Column]any, where jet.BoolExpression) (int64, error) {
+	cols, vals, where, versioned, err := c.Updating(ctx, m, where)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := exec(ctx, db, c.stmts.Update(c.Table, cols, vals, where))
+	if err != nil {
+		return 0, err
+	}
+	if versioned && rows == 0 {
+		return 0, internal.ErrStaleVersion
+	}
+	return rows, nil
+}
+
+// Delete 删除记录；启用软删除时更新删除时间（及更新时间和操作人），否则物理删除
+func (c *Convention[Tb, S]) Delete(ctx context.Context, db qrm.DB, where jet.BoolExpression) (int64, error) {
+	if where == nil {
+		return 0, errors.New("delete without where")
+	}
+
+	cols, vals := c.Deleting(ctx)
+	if len(cols) == 0 {
+		return exec(ctx, db, c.stmts.Delete(c.Table, where))
+	}
+	return exec(ctx, db, c.stmts.Update(c.Table, cols, vals, c.Scope(where)))
+}
+
+// ForceDelete 物理删除记录（忽略软删除）
+func (c *Convention[Tb, S]) ForceDelete(ctx context.Context, db qrm.DB, where jet.BoolExpression) (int64, error) {
+	if where == nil {
+		return 0, errors.New("delete without where")
+	}
+	return exec(ctx, db, c.stmts.Delete(c.Table, where))
+}
+
+// Inserting 返回插入的列和值，自动填充创建/更新时间和操作人（m 中已设置的列不覆盖）
+func (c *Convention[Tb, S]) Inserting(ctx context.Context, m map[jet.Column]any) (jet.ColumnList, []any, error) {
+	m = clone(m)
+
+	now := c.now()
+	set(m, c.CreatedAt, now)
+	set(m, c.UpdatedAt, now)
+	if operator, ok := internal.Operator(ctx); ok {
+		set(m, c.CreatedBy, operator)
+		set(m, c.UpdatedBy, operator)
+	}
+	if len(m) == 0 {
+		return nil, nil, errors.New("insert without values")
+	}
+
+	cols, vals := split(m)
+	return cols, vals, nil
+}
+
+// Updating 返回更新的列、值和条件（已排除软删除的记录），自动填充更新时间和操作人；
+// 启用乐观锁且 m 包含版本列时，以 m 中的值作为当前版本校验并加1，此时 versioned 为 true
+func (c *Convention[Tb, S]) Updating(ctx context.Context, m map[jet.Column]any, where jet.BoolExpression) (cols jet.ColumnList, vals []any, cond jet.BoolExpression, versioned bool, err error) {
+	if where == nil {
+		err = errors.New("update without where")
+		return
+	}
+
+	m = clone(m)
+
+	set(m, c.UpdatedAt, c.now())
+	if operator, ok := internal.Operator(ctx); ok {
+		set(m, c.UpdatedBy, operator)
+	}
+
+	if c.Version != nil {
+		if version, ok := m[c.Version]; ok {
+			versioned = true
+			m[c.Version] = jet.IntExp(c.Version).ADD(jet.Int(1))
+			where = where.AND(jet.StringExp(c.Version).EQ(jet.StringExp(Value(version))))
+		}
+	}
+	if len(m) == 0 {
+		err = errors.New("update without values")
+		return
+	}
+
+	cols, vals = split(m)
+	cond = c.Scope(where)
+	return
+}
+
+// Deleting 返回软删除需更新的列和值（删除时间及更新时间和操作人），未启用软删除时返回空
+func (c *Convention[Tb, S]) Deleting(ctx context.Context) (jet.ColumnList, []any) {
+	if c.DeletedAt == nil {
+		return nil, nil
+	}
+
+	now := c.now()
+	m := map[jet.Column]any{c.DeletedAt: now}
+	set(m, c.UpdatedAt, now)
+	if operator, ok := internal.Operator(ctx); ok {
+		set(m, c.UpdatedBy, operator)
+	}
+	return split(m)
+}
+
+// InsertingModel 同 Inserting，值取自 model 的 cols 列；审计列和软删除列由约定填充，忽略 model 中的值
+func (c *Convention[Tb, S]) InsertingModel(ctx context.Context, model any, cols jet.ColumnList) (jet.ColumnList, []any, error) {
+	m, err := c.model(model, cols)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c.Inserting(ctx, m)
+}
+
+// UpdatingModel 同 Updating，值取自 model 的 cols 列；审计列和软删除列由约定填充，忽略 model 中的值，
+// 启用乐观锁且 cols 包含版本列时，以 model 中的版本作为当前版本校验并加1
+func (c *Convention[Tb, S]) UpdatingModel(ctx context.Context, model any, cols jet.ColumnList, where jet.BoolExpression) (jet.ColumnList, []any, jet.BoolExpression, bool, error) {
+	m, err := c.model(model, cols)
+	if err != nil {
+		return nil, nil, nil, false, err
+	}
+	return c.Updating(ctx, m, where)
+}
+
+// model 返回 model 中除审计列和软删除列之外的列和值
+func (c *Convention[Tb, S]) model(model any, cols jet.ColumnList) (map[jet.Column]any, error) {
+	exclude := []jet.Column{c.CreatedAt, c.UpdatedAt, c.CreatedBy, c.UpdatedBy, c.DeletedAt}
+
+	cols = slices.DeleteFunc(slices.Clone(cols), func(col jet.Column) bool {
+		return slices.Contains(exclude, col)
+	})
+	vals, err := Fields(model, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[jet.Column]any, len(cols))
+	for i, col := range cols {
+		m[col] = vals[i]
+	}
+	return m, nil
+}
+
+// set 设置列的值，col 为 nil 或已设置时忽略
+func set(m map[jet.Column]any, col jet.Column, v any) {
+	if col == nil {
+		return
+	}
+	if _, ok := m[col]; ok {
+		return
+	}
+	m[col] = v
+}
+
+func clone(m map[jet.Column]any) map[jet.Column]any {
+	ret := make(map[jet.Column]any, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+	return ret
+}
+
+func split(m map[jet.Column]any) (jet.ColumnList, []any) {
+	cols := make(jet.ColumnList, 0, len(m))
+	vals := make([]any, 0, len(m))
+	for k, v := range m {
+		cols = append(cols, k)
+		vals = append(vals, v)
+	}
+	return cols, vals
+}
+
+func exec(ctx context.Context, db qrm.DB, stmt jet.Statement) (int64, error) {
+	ret, err := Exec(ctx, db, stmt)
+	if err != nil {
+		return 0, err
+	}
+	rows, _ := ret.RowsAffected()
+	return rows, nil
+}
@@ -0,0 +1,96 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	jet "github.com/go-jet/jet/v2/mysql"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// Exec 执行语句（INSERT、UPDATE、DELETE）
+func Exec(ctx context.Context, db qrm.DB, stmt jet.Statement) (sql.Result, error) {
+	var (
+		ret  sql.Result
+		rows int64 = -1
+		err  error
+	)
+
+	start := time.Now()
+	defer func() {
+		internal.Log(ctx, stmt, time.Since(start), rows, err)
+	}()
+
+	ret, err = stmt.ExecContext(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, _ = ret.RowsAffected()
+	return ret, nil
+}
+
+// FindOne 查询一条记录，不存在时返回 nil
+func FindOne[T any](ctx context.Context, db qrm.DB, stmt jet.Statement) (*T, error) {
+	var (
+		dest T
+		rows int64
+		err  error
+	)
+
+	start := time.Now()
+	defer func() {
+		internal.Log(ctx, stmt, time.Since(start), rows, err)
+	}()
+
+	if err = stmt.QueryContext(ctx, db, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			err = nil
+			return nil, nil
+		}
+		return nil, err
+	}
+	rows = 1
+	return &dest, nil
+}
+
+// FindAll 查询多条记录
+func FindAll[T any](ctx context.Context, db qrm.DB, stmt jet.Statement) ([]T, error) {
+	var (
+		dest []T
+		err  error
+	)
+
+	start := time.Now()
+	defer func() {
+		internal.Log(ctx, stmt, time.Since(start), int64(len(dest)), err)
+	}()
+
+	if err = stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+	return dest, nil
+}
+
+// Count 执行统计语句，返回 count 列的值
+func Count(ctx context.Context, db qrm.DB, stmt jet.Statement) (int64, error) {
+	var (
+		total struct {
+			Count int64
+		}
+		err error
+	)
+
+	start := time.Now()
+	defer func() {
+		internal.Log(ctx, stmt, time.Since(start), 1, err)
+	}()
+
+	if err = stmt.QueryContext(ctx, db, &total); err != nil {
+		return 0, err
+	}
+	return total.Count, nil
+}
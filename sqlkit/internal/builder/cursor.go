@@ -0,0 +1,127 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	jet "github.com/go-jet/jet/v2/mysql"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// Order 游标分页的排序字段
+type Order struct {
+	col  jet.Column
+	desc bool
+}
+
+// Asc 升序
+func Asc(col jet.Column) Order {
+	return Order{col: col}
+}
+
+// Desc 降序
+func Desc(col jet.Column) Order {
+	return Order{col: col, desc: true}
+}
+
+func (o Order) clause() jet.OrderByClause {
+	if o.desc {
+		return o.col.DESC()
+	}
+	return o.col.ASC()
+}
+
+// keyset 构建游标条件，支持混合升降序：
+//
+//	(a > x) OR (a = x AND b < y) OR (a = x AND b = y AND c > z)
+func keyset(orders []Order, values []any) jet.BoolExpression {
+	var cond jet.BoolExpression
+	for i, o := range orders {
+		var term jet.BoolExpression
+		for j := range i {
+			eq := jet.StringExp(orders[j].col).EQ(jet.StringExp(Value(values[j])))
+			if term == nil {
+				term = eq
+			} else {
+				term = term.AND(eq)
+			}
+		}
+
+		var cmp jet.BoolExpression
+		if o.desc {
+			cmp = jet.StringExp(o.col).LT(jet.StringExp(Value(values[i])))
+		} else {
+			cmp = jet.StringExp(o.col).GT(jet.StringExp(Value(values[i])))
+		}
+		if term == nil {
+			term = cmp
+		} else {
+			term = term.AND(cmp)
+		}
+
+		if cond == nil {
+			cond = term
+		} else {
+			cond = cond.OR(term)
+		}
+	}
+	return cond
+}
+
+// CursorPaginate 游标分页查询，build 根据游标条件、排序和条数构建对应方言的查询语句；
+// 返回下一页游标，为空表示没有更多数据
+func CursorPaginate[T any](ctx context.Context, db qrm.DB, build func(cond jet.BoolExpression, orderBy []jet.OrderByClause, limit int64) jet.Statement, cursor string, size int, key func(T) []any, orderBy ...Order) ([]T, string, error) {
+	if len(orderBy) == 0 {
+		return nil, "", fmt.Errorf("cursor paginate: missing order by")
+	}
+
+	cond := jet.RawBool("1 = 1")
+	if len(cursor) != 0 {
+		values, err := internal.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(values) != len(orderBy) {
+			return nil, "", fmt.Errorf("%w: expect %d values, got %d", internal.ErrInvalidCursor, len(orderBy), len(values))
+		}
+		cond = keyset(orderBy, values)
+	}
+
+	if size <= 0 {
+		size = 20
+	}
+
+	clauses := make([]jet.OrderByClause, 0, len(orderBy))
+	for _, v := range orderBy {
+		clauses = append(clauses, v.clause())
+	}
+
+	var (
+		dest []T
+		err  error
+	)
+
+	// 多查一条用于判断是否有下一页
+	stmt := build(cond, clauses, int64(size+1))
+
+	start := time.Now()
+	defer func() {
+		internal.Log(ctx, stmt, time.Since(start), int64(len(dest)), err)
+	}()
+
+	if err = stmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, "", err
+	}
+	if len(dest) <= size {
+		return dest, "", nil
+	}
+
+	dest = dest[:size]
+	next, e := internal.EncodeCursor(key(dest[size-1]))
+	if e != nil {
+		return nil, "", e
+	}
+	return dest, next, nil
+}
@@ -0,0 +1,128 @@
+package builder
+
+import (
+	"context"
+
+	jet "github.com/go-jet/jet/v2/mysql"
+)
+
+// Statements 方言相关的语句构建，由各方言基于其 Table 实现（零值可用）
+type Statements[Tb any] interface {
+	// Name 方言名称
+	Name() string
+	// Returning 是否通过 INSERT ... RETURNING 返回插入的记录
+	Returning() bool
+	// Select 查询语句，where 为 nil 表示无条件，limit <= 0 表示不限制
+	Select(table Tb, cols jet.ColumnList, where jet.BoolExpression, orderBy []jet.OrderByClause, limit, offset int64) jet.Statement
+	// Count 统计语句，返回 count 列
+	Count(table Tb, where jet.BoolExpression) jet.Statement
+	// Insert 插入语句，returning 为 RETURNING 的列（仅 Returning 为 true 时有效）
+	Insert(table Tb, cols jet.ColumnList, vals []any, returning jet.ColumnList) jet.Statement
+	// Update 更新语句
+	Update(table Tb, cols jet.ColumnList, vals []any, where jet.BoolExpression) jet.Statement
+	// Delete 删除语句
+	Delete(table Tb, where jet.BoolExpression) jet.Statement
+}
+
+// Dialect sqlkit.Dialect 的通用实现
+type Dialect[Tb any, S Statements[Tb]] struct {
+	stmts   S
+	table   Tb
+	pk      jet.Column
+	all     jet.ColumnList
+	mutable jet.ColumnList
+	conv    *Convention[Tb, S]
+}
+
+// DialectOption 方言选项
+type DialectOption[Tb any, S Statements[Tb]] func(d *Dialect[Tb, S])
+
+// WithConvention 应用表约定
+func WithConvention[Tb any, S Statements[Tb]](c *Convention[Tb, S]) DialectOption[Tb, S] {
+	return func(d *Dialect[Tb, S]) {
+		d.conv = c
+	}
+}
+
+// NewDialect 返回绑定数据表的方言
+func NewDialect[Tb any, S Statements[Tb]](table Tb, pk jet.Column, all, mutable jet.ColumnList, opts ...DialectOption[Tb, S]) *Dialect[Tb, S] {
+	d := &Dialect[Tb, S]{
+		table:   table,
+		pk:      pk,
+		all:     all,
+		mutable: mutable,
+	}
+	for _, f := range opts {
+		f(d)
+	}
+	return d
+}
+
+func (d *Dialect[Tb, S]) Name() string {
+	return d.stmts.Name()
+}
+
+func (d *Dialect[Tb, S]) Returning() bool {
+	return d.stmts.Returning()
+}
+
+func (d *Dialect[Tb, S]) PrimaryKey(id int64) jet.BoolExpression {
+	return jet.IntExp(d.pk).EQ(jet.Int(id))
+}
+
+func (d *Dialect[Tb, S]) Select(where jet.BoolExpression, orderBy []jet.OrderByClause, limit, offset int64) jet.Statement {
+	return d.stmts.Select(d.table, d.all, d.scope(where), orderBy, limit, offset)
+}
+
+func (d *Dialect[Tb, S]) Count(where jet.BoolExpression) jet.Statement {
+	return d.stmts.Count(d.table, d.scope(where))
+}
+
+func (d *Dialect[Tb, S]) Insert(ctx context.Context, model any) (jet.Statement, error) {
+	if d.conv == nil {
+		vals, err := Fields(model, d.mutable)
+		if err != nil {
+			return nil, err
+		}
+		return d.stmts.Insert(d.table, d.mutable, vals, d.all), nil
+	}
+
+	cols, vals, err := d.conv.InsertingModel(ctx, model, d.mutable)
+	if err != nil {
+		return nil, err
+	}
+	return d.stmts.Insert(d.table, cols, vals, d.all), nil
+}
+
+func (d *Dialect[Tb, S]) Update(ctx context.Context, model any, where jet.BoolExpression) (jet.Statement, bool, error) {
+	if d.conv == nil {
+		vals, err := Fields(model, d.mutable)
+		if err != nil {
+			return nil, false, err
+		}
+		return d.stmts.Update(d.table, d.mutable, vals, where), false, nil
+	}
+
+	cols, vals, cond, versioned, err := d.conv.UpdatingModel(ctx, model, d.mutable, where)
+	if err != nil {
+		return nil, false, err
+	}
+	return d.stmts.Update(d.table, cols, vals, cond), versioned, nil
+}
+
+func (d *Dialect[Tb, S]) Delete(ctx context.Context, where jet.BoolExpression) jet.Statement {
+	if d.conv != nil {
+		if cols, vals := d.conv.Deleting(ctx); len(cols) != 0 {
+			return d.stmts.Update(d.table, cols, vals, d.scope(where))
+		}
+	}
+	return d.stmts.Delete(d.table, where)
+}
+
+// scope 启用软删除时排除已删除的记录
+func (d *Dialect[Tb, S]) scope(where jet.BoolExpression) jet.BoolExpression {
+	if d.conv == nil || d.conv.DeletedAt == nil {
+		return where
+	}
+	return d.conv.Scope(where)
+}
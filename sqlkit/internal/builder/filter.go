@@ -0,0 +1,329 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	jet "github.com/go-jet/jet/v2/mysql"
+	"github.com/noble-gase/ne/protokit"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrInvalidFilter 过滤参数不合法（字段、操作符、排序不在白名单内或值格式错误）
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Op 过滤操作符
+type Op string
+
+var ops = []Op{OpEq, OpNe, OpIn, OpLike, OpGt, OpGte, OpLt, OpLte, OpRange, OpNull}
+
+const (
+	OpEq    Op = "eq"    // 等于（默认），多个值时视为 in
+	OpNe    Op = "ne"    // 不等于
+	OpIn    Op = "in"    // 包含，值以逗号分隔或重复传参
+	OpLike  Op = "like"  // 模糊匹配，值不含 % 时按包含匹配
+	OpGt    Op = "gt"    // 大于
+	OpGte   Op = "gte"   // 大于等于
+	OpLt    Op = "lt"    // 小于
+	OpLte   Op = "lte"   // 小于等于
+	OpRange Op = "range" // 闭区间，值为 min,max（任意一端可为空）
+	OpNull  Op = "null"  // true：IS NULL；false：IS NOT NULL
+)
+
+// FilterField 可过滤的字段
+type FilterField struct {
+	// Column 对应的列，值按列类型（整型、浮点、字符串、布尔）解析，其它类型原样传参
+	Column jet.Column
+	// Ops 允许的操作符，默认：eq
+	Ops []Op
+	// Sortable 是否允许排序
+	Sortable bool
+}
+
+// Filter 声明式过滤规则，将查询参数转换为 WHERE 条件和 ORDER BY
+type Filter struct {
+	literal Literal
+	fields  map[string]FilterField
+	sortKey string
+	orderBy []jet.OrderByClause
+}
+
+// NewFilter 返回过滤规则，literal 为方言的字面量构造，orderBy 为未指定排序时的默认排序
+func NewFilter(literal Literal, fields map[string]FilterField, orderBy ...jet.OrderByClause) *Filter {
+	return &Filter{
+		literal: literal,
+		fields:  fields,
+		sortKey: "sort",
+		orderBy: orderBy,
+	}
+}
+
+// SortKey 设置排序参数名，默认：sort
+func (f *Filter) SortKey(key string) *Filter {
+	f.sortKey = key
+	return f
+}
+
+// Parse 解析查询参数；未声明的普通参数（如：page、size）被忽略，
+// 带操作符的未声明字段、不允许的操作符和排序返回 ErrInvalidFilter
+func (f *Filter) Parse(values url.Values) (jet.BoolExpression, []jet.OrderByClause, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if k != f.sortKey {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var conds []jet.BoolExpression
+	for _, key := range keys {
+		name, op, explicit := f.split(key)
+		field, ok := f.fields[name]
+		if !ok {
+			if explicit {
+				return nil, nil, fmt.Errorf("%w: field %q not allowed", ErrInvalidFilter, name)
+			}
+			continue
+		}
+
+		cond, err := f.condition(name, field, op, values[key])
+		if err != nil {
+			return nil, nil, err
+		}
+		if cond != nil {
+			conds = append(conds, cond)
+		}
+	}
+
+	orderBy, err := f.sort(values.Get(f.sortKey))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(conds) == 0 {
+		return jet.RawBool("1 = 1"), orderBy, nil
+	}
+	return jet.AND(conds...), orderBy, nil
+}
+
+// ParseMessage 解析 protobuf 消息（通过 protokit.MessageToValues 转换为查询参数）
+func (f *Filter) ParseMessage(msg proto.Message) (jet.BoolExpression, []jet.OrderByClause, error) {
+	return f.Parse(protokit.MessageToValues(msg))
+}
+
+// split 解析参数名：field[op] | field_op | field
+func (f *Filter) split(key string) (string, Op, bool) {
+	if i := strings.IndexByte(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+		return key[:i], Op(key[i+1 : len(key)-1]), true
+	}
+	if _, ok := f.fields[key]; ok {
+		return key, OpEq, false
+	}
+	// 仅当后缀为已知操作符时视为 field_op，避免误判 user_agent 等未声明参数
+	if i := strings.LastIndexByte(key, '_'); i > 0 {
+		if _, ok := f.fields[key[:i]]; ok && slices.Contains(ops, Op(key[i+1:])) {
+			return key[:i], Op(key[i+1:]), true
+		}
+	}
+	return key, OpEq, false
+}
+
+func (f *Filter) condition(name string, field FilterField, op Op, raw []string) (jet.BoolExpression, error) {
+	ops := field.Ops
+	if len(ops) == 0 {
+		ops = []Op{OpEq}
+	}
+
+	// 多个值的 eq 视为 in
+	if op == OpEq && len(raw) > 1 && slices.Contains(ops, OpIn) {
+		op = OpIn
+	}
+	if !slices.Contains(ops, op) {
+		return nil, fmt.Errorf("%w: operator %q not allowed for %q", ErrInvalidFilter, op, name)
+	}
+
+	var values []string
+	for _, v := range raw {
+		if op == OpIn || op == OpRange {
+			values = append(values, strings.Split(v, ",")...)
+		} else {
+			values = append(values, v)
+		}
+	}
+	// 空值忽略
+	if !slices.ContainsFunc(values, func(v string) bool { return len(v) != 0 }) {
+		return nil, nil
+	}
+
+	invalid := func(err error) error {
+		return fmt.Errorf("%w: %q: %w", ErrInvalidFilter, name, err)
+	}
+
+	col := field.Column
+	switch op {
+	case OpIn:
+		list, err := f.list(col, values)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		return col.IN(list...), nil
+	case OpNull:
+		isNull, err := strconv.ParseBool(values[0])
+		if err != nil {
+			return nil, invalid(err)
+		}
+		if isNull {
+			return col.IS_NULL(), nil
+		}
+		return col.IS_NOT_NULL(), nil
+	case OpLike:
+		if _, ok := col.(jet.StringExpression); !ok {
+			return nil, fmt.Errorf("%w: %q is not a string column", ErrInvalidFilter, name)
+		}
+		pattern := values[0]
+		if !strings.Contains(pattern, "%") {
+			pattern = "%" + pattern + "%"
+		}
+		return jet.StringExp(col).LIKE(f.literal.String(pattern)), nil
+	case OpRange:
+		if len(values) != 2 {
+			return nil, fmt.Errorf("%w: %q range requires min,max", ErrInvalidFilter, name)
+		}
+		var conds []jet.BoolExpression
+		if len(values[0]) != 0 {
+			v, err := f.value(col, values[0])
+			if err != nil {
+				return nil, invalid(err)
+			}
+			conds = append(conds, jet.StringExp(col).GT_EQ(jet.StringExp(v)))
+		}
+		if len(values[1]) != 0 {
+			v, err := f.value(col, values[1])
+			if err != nil {
+				return nil, invalid(err)
+			}
+			conds = append(conds, jet.StringExp(col).LT_EQ(jet.StringExp(v)))
+		}
+		if len(conds) == 0 {
+			return nil, nil
+		}
+		return jet.AND(conds...), nil
+	}
+
+	v, err := f.value(col, values[0])
+	if err != nil {
+		return nil, invalid(err)
+	}
+	switch op {
+	case OpNe:
+		return jet.StringExp(col).NOT_EQ(jet.StringExp(v)), nil
+	case OpGt:
+		return jet.StringExp(col).GT(jet.StringExp(v)), nil
+	case OpGte:
+		return jet.StringExp(col).GT_EQ(jet.StringExp(v)), nil
+	case OpLt:
+		return jet.StringExp(col).LT(jet.StringExp(v)), nil
+	case OpLte:
+		return jet.StringExp(col).LT_EQ(jet.StringExp(v)), nil
+	}
+	return jet.StringExp(col).EQ(jet.StringExp(v)), nil
+}
+
+// sort 解析排序参数：-created_at,id
+func (f *Filter) sort(s string) ([]jet.OrderByClause, error) {
+	if len(s) == 0 {
+		return f.orderBy, nil
+	}
+
+	var orderBy []jet.OrderByClause
+	for _, v := range strings.Split(s, ",") {
+		desc := strings.HasPrefix(v, "-")
+		name := strings.TrimPrefix(strings.TrimPrefix(v, "-"), "+")
+
+		field, ok := f.fields[name]
+		if !ok || !field.Sortable {
+			return nil, fmt.Errorf("%w: sort by %q not allowed", ErrInvalidFilter, name)
+		}
+		if desc {
+			orderBy = append(orderBy, field.Column.DESC())
+		} else {
+			orderBy = append(orderBy, field.Column.ASC())
+		}
+	}
+	return orderBy, nil
+}
+
+// value 按列类型解析值
+func (f *Filter) value(col jet.Column, s string) (jet.Expression, error) {
+	switch col.(type) {
+	case jet.IntegerExpression:
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return f.literal.Int64(v), nil
+	case jet.FloatExpression:
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, err
+		}
+		return f.literal.Float(v), nil
+	case jet.BoolExpression:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, err
+		}
+		return f.literal.Bool(v), nil
+	case jet.StringExpression:
+		return f.literal.String(s), nil
+	}
+	return Value(s), nil
+}
+
+// list 按列类型解析 IN 查询的值
+func (f *Filter) list(col jet.Column, values []string) ([]jet.Expression, error) {
+	switch col.(type) {
+	case jet.IntegerExpression:
+		v, err := parse(values, func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) })
+		if err != nil {
+			return nil, err
+		}
+		return f.literal.Int64s(v), nil
+	case jet.FloatExpression:
+		v, err := parse(values, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+		if err != nil {
+			return nil, err
+		}
+		return f.literal.Floats(v), nil
+	case jet.BoolExpression:
+		v, err := parse(values, strconv.ParseBool)
+		if err != nil {
+			return nil, err
+		}
+		return f.literal.Bools(v), nil
+	case jet.StringExpression:
+		return f.literal.Strings(values), nil
+	}
+
+	list := make([]jet.Expression, 0, len(values))
+	for _, s := range values {
+		list = append(list, Value(s))
+	}
+	return list, nil
+}
+
+func parse[T any](values []string, fn func(s string) (T, error)) ([]T, error) {
+	ret := make([]T, 0, len(values))
+	for _, s := range values {
+		v, err := fn(s)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, v)
+	}
+	return ret, nil
+}
@@ -0,0 +1,38 @@
+package builder
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	jet "github.com/go-jet/jet/v2/mysql"
+)
+
+// Fields 按列名从 model（结构体或其指针）中依次取出各列的值，字段匹配规则同 jet 的 MODEL（如：created_at -> CreatedAt）
+func Fields(model any, cols jet.ColumnList) ([]any, error) {
+	v := reflect.Indirect(reflect.ValueOf(model))
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model must be a struct, got %T", model)
+	}
+
+	vals := make([]any, len(cols))
+	for i, col := range cols {
+		name := identifier(col.Name())
+		field := v.FieldByNameFunc(func(s string) bool {
+			return identifier(s) == name
+		})
+		if !field.IsValid() {
+			return nil, fmt.Errorf("missing struct field for column: %s", col.Name())
+		}
+		if field.Kind() == reflect.Pointer && field.IsNil() {
+			continue
+		}
+		vals[i] = reflect.Indirect(field).Interface()
+	}
+	return vals, nil
+}
+
+// identifier 忽略大小写和下划线（jet 生成的字段名为列名的驼峰形式）
+func identifier(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+}
@@ -0,0 +1,147 @@
+package builder
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+
+	jet "github.com/go-jet/jet/v2/mysql"
+	"github.com/go-jet/jet/v2/qrm"
+)
+
+// Page 分页结果
+type Page[T any] struct {
+	// List 当前页数据
+	List []T `json:"list"`
+	// Total 总记录数（WithoutCount 时为 -1）
+	Total int64 `json:"total"`
+	// Estimated Total 是否为估算值
+	Estimated bool `json:"estimated"`
+	// HasMore 是否有下一页
+	HasMore bool `json:"has_more"`
+}
+
+type pageOptions struct {
+	noCount  bool
+	parallel bool
+	estimate bool
+}
+
+// PageOption 分页选项
+type PageOption func(o *pageOptions)
+
+// WithoutCount 不统计总数，仅通过多查一条记录判断是否有下一页
+func WithoutCount() PageOption {
+	return func(o *pageOptions) {
+		o.noCount = true
+	}
+}
+
+// WithParallel 并发执行统计和数据查询（db 为 *sql.Tx 时无效）
+func WithParallel() PageOption {
+	return func(o *pageOptions) {
+		o.parallel = true
+	}
+}
+
+// WithEstimatedCount 估算总数（Pager.Estimate 为 nil 时仍执行统计）
+func WithEstimatedCount() PageOption {
+	return func(o *pageOptions) {
+		o.estimate = true
+	}
+}
+
+// Pager 分页查询的语句，由各方言构建
+type Pager struct {
+	// Select 返回数据查询语句
+	Select func(limit, offset int64) jet.Statement
+	// Count 统计语句
+	Count jet.Statement
+	// Estimate 估算总数，为 nil 表示不支持
+	Estimate func(ctx context.Context, db qrm.DB) (int64, error)
+}
+
+// Paginate 分页查询
+func Paginate[T any](ctx context.Context, db qrm.DB, pager Pager, page, size int, opts ...PageOption) (*Page[T], error) {
+	o := new(pageOptions)
+	for _, f := range opts {
+		f(o)
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+	if size <= 0 {
+		size = 20
+	}
+	offset := (page - 1) * size
+
+	ret := &Page[T]{Total: -1}
+
+	// 多查一条用于判断是否有下一页
+	query := func() error {
+		list, err := FindAll[T](ctx, db, pager.Select(int64(size+1), int64(offset)))
+		if err != nil {
+			return err
+		}
+		if len(list) > size {
+			list = list[:size]
+			ret.HasMore = true
+		}
+		ret.List = list
+		return nil
+	}
+	count := func() (err error) {
+		if o.estimate && pager.Estimate != nil {
+			ret.Total, err = pager.Estimate(ctx, db)
+			ret.Estimated = true
+			return
+		}
+		ret.Total, err = Count(ctx, db, pager.Count)
+		return
+	}
+
+	switch {
+	case o.noCount:
+		if err := query(); err != nil {
+			return nil, err
+		}
+	case o.parallel && !isTx(db):
+		var (
+			wg       sync.WaitGroup
+			countErr error
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			countErr = count()
+		}()
+		queryErr := query()
+		wg.Wait()
+		if err := errors.Join(countErr, queryErr); err != nil {
+			return nil, err
+		}
+	default:
+		if err := count(); err != nil {
+			return nil, err
+		}
+		if ret.Total == 0 && !ret.Estimated {
+			ret.List = []T{}
+			return ret, nil
+		}
+		if err := query(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ret.List == nil {
+		ret.List = []T{}
+	}
+	return ret, nil
+}
+
+func isTx(db qrm.DB) bool {
+	_, ok := db.(*sql.Tx)
+	return ok
+}
@@ -0,0 +1,77 @@
+package builder
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	jet "github.com/go-jet/jet/v2/mysql"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// Stream 流式查询，逐行扫描记录；迭代提前结束时自动关闭 rows
+func Stream[T any](ctx context.Context, db qrm.DB, stmt jet.Statement) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var (
+			rows  *jet.Rows
+			count int64
+			err   error
+		)
+
+		start := time.Now()
+		defer func() {
+			internal.Log(ctx, stmt, time.Since(start), count, err)
+		}()
+
+		rows, err = stmt.Rows(ctx, db)
+		if err != nil {
+			var zero T
+			yield(zero, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var dest T
+			if err = rows.Scan(&dest); err != nil {
+				yield(dest, err)
+				return
+			}
+			count++
+			if !yield(dest, nil) {
+				return
+			}
+		}
+		if err = rows.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// StreamChunk 流式查询，按 size 条记录分批返回
+func StreamChunk[T any](ctx context.Context, db qrm.DB, stmt jet.Statement, size int) iter.Seq2[[]T, error] {
+	if size <= 0 {
+		size = 1000
+	}
+	return func(yield func([]T, error) bool) {
+		chunk := make([]T, 0, size)
+		for v, err := range Stream[T](ctx, db, stmt) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			chunk = append(chunk, v)
+			if len(chunk) >= size {
+				if !yield(chunk, nil) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) != 0 {
+			yield(chunk, nil)
+		}
+	}
+}
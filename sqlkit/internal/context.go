@@ -0,0 +1,22 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrStaleVersion 乐观锁版本冲突
+var ErrStaleVersion = errors.New("stale version")
+
+type operatorKey struct{}
+
+// WithOperator 在 ctx 中设置操作人
+func WithOperator(ctx context.Context, operator any) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// Operator 返回 ctx 中的操作人
+func Operator(ctx context.Context) (any, bool) {
+	v := ctx.Value(operatorKey{})
+	return v, v != nil
+}
@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidCursor 游标格式错误
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorValue 游标中的排序值（带类型，解码后保持原类型）
+type cursorValue struct {
+	T string `json:"t"`
+	V string `json:"v"`
+}
+
+// EncodeCursor 将最后一行的排序值编码为 base64 游标
+func EncodeCursor(values []any) (string, error) {
+	list := make([]cursorValue, 0, len(values))
+	for _, v := range values {
+		if valuer, ok := v.(driver.Valuer); ok {
+			dv, err := valuer.Value()
+			if err != nil {
+				return "", err
+			}
+			v = dv
+		}
+
+		var cv cursorValue
+		switch x := v.(type) {
+		case nil:
+			cv = cursorValue{T: "n"}
+		case bool:
+			cv = cursorValue{T: "b", V: strconv.FormatBool(x)}
+		case int:
+			cv = cursorValue{T: "i", V: strconv.FormatInt(int64(x), 10)}
+		case int8:
+			cv = cursorValue{T: "i", V: strconv.FormatInt(int64(x), 10)}
+		case int16:
+			cv = cursorValue{T: "i", V: strconv.FormatInt(int64(x), 10)}
+		case int32:
+			cv = cursorValue{T: "i", V: strconv.FormatInt(int64(x), 10)}
+		case int64:
+			cv = cursorValue{T: "i", V: strconv.FormatInt(x, 10)}
+		case uint:
+			cv = cursorValue{T: "u", V: strconv.FormatUint(uint64(x), 10)}
+		case uint8:
+			cv = cursorValue{T: "u", V: strconv.FormatUint(uint64(x), 10)}
+		case uint16:
+			cv = cursorValue{T: "u", V: strconv.FormatUint(uint64(x), 10)}
+		case uint32:
+			cv = cursorValue{T: "u", V: strconv.FormatUint(uint64(x), 10)}
+		case uint64:
+			cv = cursorValue{T: "u", V: strconv.FormatUint(x, 10)}
+		case float32:
+			cv = cursorValue{T: "f", V: strconv.FormatFloat(float64(x), 'g', -1, 32)}
+		case float64:
+			cv = cursorValue{T: "f", V: strconv.FormatFloat(x, 'g', -1, 64)}
+		case string:
+			cv = cursorValue{T: "s", V: x}
+		case []byte:
+			cv = cursorValue{T: "x", V: base64.RawURLEncoding.EncodeToString(x)}
+		case time.Time:
+			cv = cursorValue{T: "t", V: x.Format(time.RFC3339Nano)}
+		default:
+			return "", fmt.Errorf("unsupported cursor value type: %T", v)
+		}
+		list = append(list, cv)
+	}
+
+	b, err := json.Marshal(list)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor 解码游标，返回排序值
+func DecodeCursor(cursor string) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	var list []cursorValue
+	if err = json.Unmarshal(b, &list); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	values := make([]any, 0, len(list))
+	for _, cv := range list {
+		var (
+			v   any
+			err error
+		)
+		switch cv.T {
+		case "n":
+			v = nil
+		case "b":
+			v, err = strconv.ParseBool(cv.V)
+		case "i":
+			v, err = strconv.ParseInt(cv.V, 10, 64)
+		case "u":
+			v, err = strconv.ParseUint(cv.V, 10, 64)
+		case "f":
+			v, err = strconv.ParseFloat(cv.V, 64)
+		case "s":
+			v = cv.V
+		case "x":
+			v, err = base64.RawURLEncoding.DecodeString(cv.V)
+		case "t":
+			v, err = time.Parse(time.RFC3339Nano, cv.V)
+		default:
+			err = fmt.Errorf("unknown type(%s)", cv.T)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
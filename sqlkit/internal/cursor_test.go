@@ -0,0 +1,22 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 123456789, time.UTC)
+
+	cursor, err := EncodeCursor([]any{int32(1), uint64(2), "hello", now, nil, true})
+	assert.Nil(t, err)
+
+	values, err := DecodeCursor(cursor)
+	assert.Nil(t, err)
+	assert.Equal(t, []any{int64(1), uint64(2), "hello", now, nil, true}, values)
+
+	_, err = DecodeCursor("invalid")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
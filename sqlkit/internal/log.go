@@ -0,0 +1,329 @@
+package internal
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Query SQL执行日志
+type Query struct {
+	// SQL 参数化的SQL（已压缩）
+	SQL string
+	// Args 参数
+	Args []any
+	// Fingerprint 归一化指纹（字面量替换为 ?），用于聚合统计
+	Fingerprint string
+	// Duration 耗时
+	Duration time.Duration
+	// Rows 影响或返回的行数，未知时为 -1
+	Rows int64
+	// Err 错误
+	Err error
+}
+
+type LogFunc = func(ctx context.Context, q Query)
+
+var Logger LogFunc
+
+// Statement 可生成参数化SQL的语句（jet 语句均已实现）
+type Statement interface {
+	Sql() (query string, args []any)
+}
+
+// Log 记录语句的执行日志
+func Log(ctx context.Context, stmt Statement, cost time.Duration, rows int64, err error) {
+	if Logger == nil {
+		return
+	}
+	query, args := stmt.Sql()
+	LogSQL(ctx, query, args, cost, rows, err)
+}
+
+// LogSQL 记录SQL的执行日志
+func LogSQL(ctx context.Context, query string, args []any, cost time.Duration, rows int64, err error) {
+	if Logger == nil {
+		return
+	}
+	Logger(ctx, Query{
+		SQL:         Minify(query),
+		Args:        args,
+		Fingerprint: Fingerprint(query),
+		Duration:    cost,
+		Rows:        rows,
+		Err:         err,
+	})
+}
+
+type tokenKind int
+
+const (
+	tokIdent tokenKind = iota
+	tokPlaceholder
+	tokLiteral
+	tokPunct
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize 将SQL拆分为词法单元（忽略注释和空白）
+func tokenize(sql string) []token {
+	var (
+		tokens []token
+		runes  = []rune(sql)
+		n      = len(runes)
+	)
+
+	for i := 0; i < n; i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+
+		case r == '-' && i+1 < n && runes[i+1] == '-':
+			for i < n && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < n && runes[i+1] == '*':
+			i += 2
+			for i+1 < n && (runes[i] != '*' || runes[i+1] != '/') {
+				i++
+			}
+			i++
+
+		case r == '\'':
+			j := i + 1
+			for ; j < n; j++ {
+				if runes[j] == '\\' {
+					j++
+					continue
+				}
+				if runes[j] == '\'' {
+					if j+1 < n && runes[j+1] == '\'' {
+						j++
+						continue
+					}
+					break
+				}
+			}
+			tokens = append(tokens, token{kind: tokLiteral})
+			i = j
+
+		case r == '?':
+			tokens = append(tokens, token{kind: tokPlaceholder, text: "?"})
+
+		case r == '$' && i+1 < n && isDigit(runes[i+1]):
+			j := i + 1
+			for j < n && isDigit(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokPlaceholder, text: string(runes[i:j])})
+			i = j - 1
+
+		case r == '(' || r == ')' || r == ',' || r == ';':
+			tokens = append(tokens, token{kind: tokPunct, text: string(r)})
+
+		case isIdent(r) || r == '`' || r == '"':
+			// 标识符（含 table.column 和引号标识符）
+			j := i
+			for j < n {
+				if runes[j] == '`' || runes[j] == '"' {
+					q := runes[j]
+					j++
+					for j < n && runes[j] != q {
+						j++
+					}
+					j++
+					continue
+				}
+				if isIdent(runes[j]) || runes[j] == '.' {
+					j++
+					continue
+				}
+				break
+			}
+			j = min(j, n)
+			text := string(runes[i:j])
+			kind := tokIdent
+			if isDigit(r) {
+				kind = tokLiteral
+			}
+			tokens = append(tokens, token{kind: kind, text: text})
+			i = j - 1
+
+		default:
+			// 运算符
+			j := i
+			for j < n && strings.ContainsRune("=<>!~:+-*/%|&^", runes[j]) {
+				j++
+			}
+			if j == i {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokOp, text: string(runes[i:j])})
+			i = j - 1
+		}
+	}
+	return tokens
+}
+
+// ArgColumns 推断参数化SQL中每个占位符对应的列名（无法推断时为空），用于参数脱敏；
+// 支持 col = ?、col IN (?, ?)、col BETWEEN ? AND ?、INSERT INTO t (a, b) VALUES (?, ?)、
+// UPDATE t SET (a, b) = (?, ?) 等形式
+func ArgColumns(sql string) []string {
+	tokens := tokenize(sql)
+
+	var (
+		cols      []string
+		tupleCols []string // INSERT 或 SET (a, b) 的列名
+		setTuple  bool     // SET (a, b) 之后，等待 = (?, ?)
+		inValues  bool
+		depth     int
+		index     int
+	)
+
+	for i, t := range tokens {
+		switch t.kind {
+		case tokIdent:
+			switch strings.ToUpper(t.text) {
+			case "INSERT", "REPLACE":
+				tupleCols = insertColumns(tokens[i:])
+			case "VALUES":
+				// MySQL 的 VALUES(col) 函数不是 VALUES 子句
+				inValues = depth == 0 && len(tupleCols) != 0
+			case "SET":
+				// PostgreSQL 多列更新：SET (a, b) = (?, ?)
+				if depth == 0 && i+1 < len(tokens) && tokens[i+1].text == "(" {
+					tupleCols = insertColumns(tokens[i:])
+					setTuple = len(tupleCols) != 0
+				}
+			default:
+				if depth == 0 {
+					inValues = false
+				}
+			}
+
+		case tokOp:
+			if setTuple && depth == 0 && t.text == "=" {
+				setTuple = false
+				inValues = true
+			}
+
+		case tokPunct:
+			switch t.text {
+			case "(":
+				depth++
+				if inValues && depth == 1 {
+					index = 0
+				}
+			case ")":
+				depth--
+			case ",":
+				if inValues && depth == 1 {
+					index++
+				}
+			}
+
+		case tokPlaceholder:
+			col := ""
+			if inValues {
+				if depth == 1 && index < len(tupleCols) {
+					col = tupleCols[index]
+				}
+			} else {
+				col = lookBack(tokens[:i], cols)
+			}
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// insertColumns 解析 INSERT INTO t (a, b, c) 或 SET (a, b, c) 的列名
+func insertColumns(tokens []token) []string {
+	i := 1
+	for i < len(tokens) && tokens[i].kind == tokIdent && tokens[i].text != "(" {
+		i++
+	}
+	if i >= len(tokens) || tokens[i].text != "(" {
+		return nil
+	}
+
+	var cols []string
+	for i++; i < len(tokens) && tokens[i].text != ")"; i++ {
+		if tokens[i].kind == tokIdent {
+			cols = append(cols, columnName(tokens[i].text))
+		}
+	}
+	return cols
+}
+
+// lookBack 根据占位符之前的词法单元推断列名
+func lookBack(tokens []token, cols []string) string {
+	j := len(tokens) - 1
+
+	skipParen := func() {
+		for j >= 0 && tokens[j].text == "(" {
+			j--
+		}
+	}
+
+	skipParen()
+	if j >= 0 && tokens[j].text == "," {
+		// 列表：回溯到左括号
+		depth := 0
+		for ; j >= 0; j-- {
+			if tokens[j].text == ")" {
+				depth++
+			} else if tokens[j].text == "(" {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+		}
+		j--
+		skipParen()
+	}
+	if j < 0 {
+		return ""
+	}
+
+	op := strings.ToUpper(tokens[j].text)
+	switch op {
+	case "AND":
+		// BETWEEN ? AND ?
+		k := j - 1
+		for k >= 0 && (tokens[k].kind == tokPlaceholder || tokens[k].kind == tokOp || tokens[k].text == "(" || tokens[k].text == ")") {
+			k--
+		}
+		if k >= 0 && strings.ToUpper(tokens[k].text) == "BETWEEN" && len(cols) != 0 {
+			return cols[len(cols)-1]
+		}
+		return ""
+	case "=", "!=", "<>", "<", ">", "<=", ">=", "LIKE", "ILIKE", "IN", "BETWEEN", "IS":
+	default:
+		return ""
+	}
+
+	j--
+	if j >= 0 && strings.ToUpper(tokens[j].text) == "NOT" {
+		j--
+	}
+	if j >= 0 && tokens[j].kind == tokIdent {
+		return columnName(tokens[j].text)
+	}
+	return ""
+}
+
+// columnName 去除表名和引号，返回列名
+func columnName(s string) string {
+	if i := strings.LastIndexByte(s, '.'); i >= 0 {
+		s = s[i+1:]
+	}
+	return strings.Trim(s, "`\"")
+}
@@ -1,16 +1,36 @@
 package internal
 
 import (
-	"context"
+	"regexp"
 	"strings"
-	"time"
 )
 
-type LogFunc = func(ctx context.Context, sql string, cost time.Duration, err error)
+// Minify 压缩SQL：去除注释和多余空白
+func Minify(sql string) string {
+	return minify(sql, false)
+}
 
-var Logger LogFunc
+// Fingerprint 返回SQL的归一化指纹：在 Minify 的基础上将字面量和占位符替换为 ?，
+// 并合并 IN 列表和 VALUES 多行，用于慢查询聚合统计
+//
+//	SELECT * FROM demo WHERE id IN (1, 2, 3) AND name = 'hello'
+//	=> SELECT * FROM demo WHERE id IN (?) AND name = ?
+func Fingerprint(sql string) string {
+	s := minify(sql, true)
+	s = listRegex.ReplaceAllString(s, "?")
+	s = tupleRegex.ReplaceAllString(s, "(?)")
+	return s
+}
 
-func Minify(sql string) string {
+var (
+	// ?, ?, ? => ?
+	listRegex = regexp.MustCompile(`\?(\s*,\s*\?)+`)
+	// (?), (?) => (?)
+	tupleRegex = regexp.MustCompile(`\(\?\)(\s*,\s*\(\?\))+`)
+)
+
+// minify 压缩SQL，normalize 为 true 时将字面量和占位符替换为 ?
+func minify(sql string, normalize bool) string {
 	var (
 		inSingle     bool
 		inDouble     bool
@@ -46,6 +66,32 @@ func Minify(sql string) string {
 				lastWasSpace = true
 			}
 
+		case normalize && !inDouble && r == '\'':
+			// 字符串字面量替换为 ?
+			for i++; i < n; i++ {
+				if runes[i] == '\\' {
+					i++
+					continue
+				}
+				if runes[i] == '\'' {
+					if i+1 < n && runes[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			out.WriteRune('?')
+			lastWasSpace = false
+
+		case normalize && !inDouble && (isDigit(r) || (r == '$' && i+1 < n && isDigit(runes[i+1]))) && (i == 0 || !isIdent(runes[i-1])):
+			// 数字字面量和 $n 占位符替换为 ?
+			for i+1 < n && (isIdent(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+			out.WriteRune('?')
+			lastWasSpace = false
+
 		case r == '\'':
 			if !inDouble {
 				// 处理 '' 转义
@@ -92,3 +138,11 @@ func Minify(sql string) string {
 	}
 	return strings.TrimSpace(out.String())
 }
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdent(r rune) bool {
+	return r == '_' || isDigit(r) || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
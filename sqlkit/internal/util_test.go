@@ -17,3 +17,25 @@ WHERE
 
 	assert.Equal(t, "SELECT id, name FROM demo WHERE name LIKE '%hello%';", Minify(s))
 }
+
+func TestFingerprint(t *testing.T) {
+	s := `SELECT id, name
+FROM demo -- comment
+WHERE id IN (1, 2, 3) AND name = 'it''s' AND t1.score > $1::integer;`
+
+	assert.Equal(t, "SELECT id, name FROM demo WHERE id IN (?) AND name = ? AND t1.score > ?::integer;", Fingerprint(s))
+	assert.Equal(t, "INSERT INTO demo (id, name) VALUES (?);", Fingerprint("INSERT INTO demo (id, name) VALUES (1, 'a'), (2, 'b');"))
+}
+
+func TestArgColumns(t *testing.T) {
+	assert.Equal(t, []string{"name", "phone", "id", "id", "age", "age", ""},
+		ArgColumns("SELECT * FROM demo WHERE (demo.name = ?) AND `phone` LIKE ? AND demo.id NOT IN (?, ?) AND age BETWEEN ? AND ? LIMIT ?"))
+
+	assert.Equal(t, []string{"name", "password", "name", "password", "password"},
+		ArgColumns(`INSERT INTO demo ("name", "password") VALUES ($1::text, $2), ($3, $4) ON CONFLICT (name) DO UPDATE SET password = $5`))
+
+	assert.Equal(t, []string{"password", "id"}, ArgColumns("UPDATE demo SET password = ? WHERE demo.id > (?)"))
+
+	assert.Equal(t, []string{"name", "password", "id"},
+		ArgColumns("UPDATE public.demo\nSET (name, password) = ($1::text, $2)\nWHERE demo.id = $3;"))
+}
@@ -0,0 +1,79 @@
+package sqlkit
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// Query SQL执行日志
+//
+//	SQL         参数化的SQL（不包含参数值）
+//	Args        参数
+//	Fingerprint 归一化指纹（字面量替换为 ?），用于慢查询聚合统计
+//	Duration    耗时
+//	Rows        影响或返回的行数，未知时为 -1
+//	Err         错误
+type Query = internal.Query
+
+// LogFunc SQL日志函数
+type LogFunc = internal.LogFunc
+
+// SetLogger 设置SQL日志函数，可使用 SlowQuery、Sample、Redact 组合过滤
+//
+//	sqlkit.SetLogger(sqlkit.SlowQuery(200*time.Millisecond, sqlkit.Redact(func(ctx context.Context, q sqlkit.Query) {
+//		slog.InfoContext(ctx, "[sqlkit] query", slog.String("sql", q.SQL), slog.Any("args", q.Args), slog.Duration("duration", q.Duration))
+//	}, "password", "phone")))
+func SetLogger(fn LogFunc) {
+	internal.Logger = fn
+}
+
+// SlowQuery 仅记录耗时超过 threshold 的查询（执行出错的查询总是记录）
+func SlowQuery(threshold time.Duration, fn LogFunc) LogFunc {
+	return func(ctx context.Context, q Query) {
+		if q.Err != nil || q.Duration >= threshold {
+			fn(ctx, q)
+		}
+	}
+}
+
+// Sample 按比例 rate（0~1）采样记录（执行出错的查询总是记录）
+func Sample(rate float64, fn LogFunc) LogFunc {
+	return func(ctx context.Context, q Query) {
+		if q.Err != nil || rand.Float64() < rate {
+			fn(ctx, q)
+		}
+	}
+}
+
+// Redact 将指定列对应的参数替换为 ******（列名不区分大小写）
+//
+// 列名根据SQL推断，支持：col = ?、col IN (?, ?)、col BETWEEN ? AND ?、INSERT INTO t (a, b) VALUES (?, ?) 等形式
+func Redact(fn LogFunc, columns ...string) LogFunc {
+	set := make(map[string]struct{}, len(columns))
+	for _, v := range columns {
+		set[strings.ToLower(v)] = struct{}{}
+	}
+	return func(ctx context.Context, q Query) {
+		if len(q.Args) == 0 || len(set) == 0 {
+			fn(ctx, q)
+			return
+		}
+
+		args := slices.Clone(q.Args)
+		for i, col := range internal.ArgColumns(q.SQL) {
+			if i >= len(args) {
+				break
+			}
+			if _, ok := set[strings.ToLower(col)]; ok {
+				args[i] = "******"
+			}
+		}
+		q.Args = args
+		fn(ctx, q)
+	}
+}
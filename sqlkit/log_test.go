@@ -0,0 +1,59 @@
+package sqlkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-jet/jet/v2/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogFilter(t *testing.T) {
+	ctx := context.Background()
+
+	var logged []Query
+	fn := func(ctx context.Context, q Query) {
+		logged = append(logged, q)
+	}
+
+	slow := SlowQuery(100*time.Millisecond, fn)
+	slow(ctx, Query{SQL: "SELECT 1", Duration: time.Millisecond})
+	slow(ctx, Query{SQL: "SELECT 2", Duration: time.Second})
+	slow(ctx, Query{SQL: "SELECT 3", Duration: time.Millisecond, Err: errors.New("oops")})
+	if assert.Len(t, logged, 2) {
+		assert.Equal(t, "SELECT 2", logged[0].SQL)
+		assert.Equal(t, "SELECT 3", logged[1].SQL)
+	}
+
+	logged = nil
+	sample := Sample(0, fn)
+	sample(ctx, Query{SQL: "SELECT 1"})
+	assert.Len(t, logged, 0)
+
+	logged = nil
+	args := []any{"hello", "secret", int64(1)}
+	Redact(fn, "Password")(ctx, Query{
+		SQL:  "UPDATE demo SET name = ?, password = ? WHERE id = ?",
+		Args: args,
+	})
+	if assert.Len(t, logged, 1) {
+		assert.Equal(t, []any{"hello", "******", int64(1)}, logged[0].Args)
+	}
+	// 不修改原参数
+	assert.Equal(t, "secret", args[1])
+
+	// PostgreSQL 多列更新：SET (name, password) = ($1, $2)
+	id := postgres.IntegerColumn("id")
+	name := postgres.StringColumn("name")
+	password := postgres.StringColumn("password")
+	demo := postgres.NewTable("public", "demo", "", id, name, password)
+	query, args := demo.UPDATE(name, password).SET("hello", "secret").WHERE(id.EQ(postgres.Int(1))).Sql()
+
+	logged = nil
+	Redact(fn, "password")(ctx, Query{SQL: query, Args: args})
+	if assert.Len(t, logged, 1) {
+		assert.Equal(t, []any{"hello", "******", int64(1)}, logged[0].Args)
+	}
+}
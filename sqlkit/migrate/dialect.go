@@ -0,0 +1,117 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// ErrLockTimeout 获取迁移锁超时
+var ErrLockTimeout = errors.New("migrate: acquire lock timeout")
+
+type dialect interface {
+	// placeholder 返回第 i 个参数的占位符（从1开始）
+	placeholder(i int) string
+	// lock 获取迁移锁，确保只有一个实例执行迁移
+	lock(ctx context.Context, conn *sql.Conn, key string, timeout time.Duration) error
+	// unlock 释放迁移锁
+	unlock(ctx context.Context, conn *sql.Conn, key string) error
+}
+
+func newDialect(driver string) (dialect, error) {
+	switch driver {
+	case "mysql":
+		return mysql{}, nil
+	case "pgx", "postgres":
+		return pgsql{}, nil
+	case "sqlite3", "sqlite":
+		return sqlite{}, nil
+	}
+	return nil, fmt.Errorf("migrate: unsupported driver(%s)", driver)
+}
+
+type mysql struct{}
+
+func (mysql) placeholder(int) string {
+	return "?"
+}
+
+// lock 使用 GET_LOCK
+func (mysql) lock(ctx context.Context, conn *sql.Conn, key string, timeout time.Duration) error {
+	var ret sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", key, int64(timeout.Seconds())).Scan(&ret); err != nil {
+		return err
+	}
+	if !ret.Valid || ret.Int64 != 1 {
+		return ErrLockTimeout
+	}
+	return nil
+}
+
+func (mysql) unlock(ctx context.Context, conn *sql.Conn, key string) error {
+	_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", key)
+	return err
+}
+
+type pgsql struct{}
+
+func (pgsql) placeholder(i int) string {
+	return "$" + strconv.Itoa(i)
+}
+
+// lock 使用 Advisory Lock（会话级）
+func (pgsql) lock(ctx context.Context, conn *sql.Conn, key string, timeout time.Duration) error {
+	id := advisoryKey(key)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		var ok bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&ok); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return ErrLockTimeout
+			}
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ErrLockTimeout
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (pgsql) unlock(ctx context.Context, conn *sql.Conn, key string) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", advisoryKey(key))
+	return err
+}
+
+// sqlite 单文件数据库，无需加锁
+type sqlite struct{}
+
+func (sqlite) placeholder(int) string {
+	return "?"
+}
+
+func (sqlite) lock(context.Context, *sql.Conn, string, time.Duration) error {
+	return nil
+}
+
+func (sqlite) unlock(context.Context, *sql.Conn, string) error {
+	return nil
+}
+
+func advisoryKey(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,346 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// ErrChecksum 已执行的迁移文件被修改
+var ErrChecksum = errors.New("migrate: checksum mismatch")
+
+// Status 迁移状态
+type Status struct {
+	*Migration
+	// Applied 是否已执行
+	Applied bool
+	// AppliedAt 执行时间
+	AppliedAt time.Time
+	// Modified 已执行后文件被修改（校验和不一致）
+	Modified bool
+}
+
+type record struct {
+	version   int64
+	checksum  string
+	appliedAt time.Time
+}
+
+type options struct {
+	table       string
+	lockKey     string
+	lockTimeout time.Duration
+	dryRun      bool
+}
+
+// Option 迁移选项
+type Option func(o *options)
+
+// WithTable 设置迁移历史表名，默认：schema_migrations
+func WithTable(name string) Option {
+	return func(o *options) {
+		o.table = name
+	}
+}
+
+// WithLockKey 设置迁移锁的名称，默认：ne:migrate:<table>
+func WithLockKey(key string) Option {
+	return func(o *options) {
+		o.lockKey = key
+	}
+}
+
+// WithLockTimeout 设置获取迁移锁的超时时间，默认：1m
+func WithLockTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.lockTimeout = d
+	}
+}
+
+// WithDryRun 仅输出将要执行的迁移，不实际执行（迁移历史表不存在时仍会创建）
+func WithDryRun() Option {
+	return func(o *options) {
+		o.dryRun = true
+	}
+}
+
+// Migrator 数据库版本迁移，支持：MySQL、PostgreSQL、SQLite
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	fsys, _ := fs.Sub(migrations, "migrations")
+//	m, err := migrate.New(db, "mysql", fsys)
+//	if err != nil {
+//		return err
+//	}
+//	m.Up(ctx)
+//
+// 迁移文件命名：<version>_<name>.up.sql 和 <version>_<name>.down.sql（可选），如：
+//
+//	20240101120000_create_user.up.sql
+//	20240101120000_create_user.down.sql
+//
+// 注意：MySQL 的 DDL 会隐式提交事务，执行失败时可能需要人工处理
+type Migrator struct {
+	db      *sql.DB
+	dialect dialect
+	fsys    fs.FS
+	opts    *options
+}
+
+// Up 执行所有未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up(ctx context.Context) ([]*Migration, error) {
+	return m.UpTo(ctx, -1)
+}
+
+// UpTo 执行版本号 <= version 的未执行迁移（version<0 表示全部），返回本次执行的迁移
+func (m *Migrator) UpTo(ctx context.Context, version int64) ([]*Migration, error) {
+	migrations, err := load(m.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*Migration
+	err = m.locked(ctx, func(conn *sql.Conn, applied map[int64]*record) error {
+		if err := verify(migrations, applied); err != nil {
+			return err
+		}
+
+		for _, v := range migrations {
+			if version >= 0 && v.Version > version {
+				break
+			}
+			if _, ok := applied[v.Version]; ok {
+				continue
+			}
+
+			if err := m.apply(ctx, conn, v, v.Up, true); err != nil {
+				return fmt.Errorf("up %d_%s: %w", v.Version, v.Name, err)
+			}
+			ret = append(ret, v)
+		}
+		return nil
+	})
+	return ret, err
+}
+
+// Down 回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	migrations, err := load(m.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*Migration
+	err = m.locked(ctx, func(conn *sql.Conn, applied map[int64]*record) error {
+		for _, v := range slices.Backward(migrations) {
+			if len(ret) >= steps {
+				break
+			}
+			if _, ok := applied[v.Version]; !ok {
+				continue
+			}
+			if len(v.Down) == 0 {
+				return fmt.Errorf("down %d_%s: missing down file", v.Version, v.Name)
+			}
+
+			if err := m.apply(ctx, conn, v, v.Down, false); err != nil {
+				return fmt.Errorf("down %d_%s: %w", v.Version, v.Name, err)
+			}
+			ret = append(ret, v)
+		}
+		return nil
+	})
+	return ret, err
+}
+
+// Status 返回所有迁移的执行状态
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	migrations, err := load(m.fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err = m.createTable(ctx, conn); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*Status, 0, len(migrations))
+	for _, v := range migrations {
+		status := &Status{Migration: v}
+		if r, ok := applied[v.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = r.appliedAt
+			status.Modified = r.checksum != v.Checksum
+		}
+		ret = append(ret, status)
+	}
+	return ret, nil
+}
+
+// locked 获取迁移锁后执行 fn
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn, applied map[int64]*record) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if !m.opts.dryRun {
+		if err = m.dialect.lock(ctx, conn, m.opts.lockKey, m.opts.lockTimeout); err != nil {
+			return err
+		}
+		defer func() {
+			if e := m.dialect.unlock(context.WithoutCancel(ctx), conn, m.opts.lockKey); e != nil {
+				slog.LogAttrs(ctx, slog.LevelError, "[sqlkit:migrate] release lock failed", slog.String("key", m.opts.lockKey), slog.Any("error", e))
+			}
+		}()
+	}
+
+	if err = m.createTable(ctx, conn); err != nil {
+		return err
+	}
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return fn(conn, applied)
+}
+
+// apply 在事务中执行迁移SQL并更新迁移历史
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, v *Migration, script string, up bool) error {
+	stmts := split(script)
+
+	if m.opts.dryRun {
+		direction := "up"
+		if !up {
+			direction = "down"
+		}
+		slog.LogAttrs(ctx, slog.LevelInfo, "[sqlkit:migrate] dry-run", slog.String("direction", direction), slog.Int64("version", v.Version), slog.String("name", v.Name), slog.Any("stmts", stmts))
+		return nil
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	exec := func(query string, args ...any) error {
+		start := time.Now()
+		ret, err := tx.ExecContext(ctx, query, args...)
+		rows := int64(-1)
+		if err == nil {
+			rows, _ = ret.RowsAffected()
+		}
+		internal.LogSQL(ctx, query, args, time.Since(start), rows, err)
+		return err
+	}
+
+	for _, stmt := range stmts {
+		if err = exec(stmt); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	p := m.dialect.placeholder
+	if up {
+		err = exec(fmt.Sprintf("INSERT INTO %s (version, name, checksum, applied_at) VALUES (%s, %s, %s, %s)", m.opts.table, p(1), p(2), p(3), p(4)),
+			v.Version, v.Name, v.Checksum, time.Now().UnixMilli())
+	} else {
+		err = exec(fmt.Sprintf("DELETE FROM %s WHERE version = %s", m.opts.table, p(1)), v.Version)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) createTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	checksum VARCHAR(64) NOT NULL,
+	applied_at BIGINT NOT NULL
+)`, m.opts.table))
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]*record, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, checksum, applied_at FROM %s", m.opts.table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make(map[int64]*record)
+	for rows.Next() {
+		var (
+			r  record
+			ms int64
+		)
+		if err = rows.Scan(&r.version, &r.checksum, &ms); err != nil {
+			return nil, err
+		}
+		r.appliedAt = time.UnixMilli(ms)
+		ret[r.version] = &r
+	}
+	return ret, rows.Err()
+}
+
+// verify 校验已执行迁移的文件是否被修改
+func verify(migrations []*Migration, applied map[int64]*record) error {
+	for _, v := range migrations {
+		if r, ok := applied[v.Version]; ok && r.checksum != v.Checksum {
+			return fmt.Errorf("%w: %d_%s", ErrChecksum, v.Version, v.Name)
+		}
+	}
+	return nil
+}
+
+// New 返回一个迁移器，迁移文件位于 fsys 的根目录
+//
+//	driver: mysql | pgx | sqlite3
+func New(db *sql.DB, driver string, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	d, err := newDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &options{
+		table:       "schema_migrations",
+		lockTimeout: time.Minute,
+	}
+	for _, f := range opts {
+		f(o)
+	}
+	if len(o.lockKey) == 0 {
+		o.lockKey = "ne:migrate:" + o.table
+	}
+
+	return &Migrator{
+		db:      db,
+		dialect: d,
+		fsys:    fsys,
+		opts:    o,
+	}, nil
+}
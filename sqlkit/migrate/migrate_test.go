@@ -0,0 +1,100 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	script := `-- create table
+CREATE TABLE demo (
+	id INTEGER PRIMARY KEY, -- id
+	name TEXT DEFAULT 'a;b'
+);
+/* trigger */
+CREATE FUNCTION f() RETURNS trigger AS $body$
+BEGIN
+	RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql;
+-- end
+`
+	stmts := split(script)
+	assert.Equal(t, 2, len(stmts))
+	assert.Equal(t, `CREATE TABLE demo (
+	id INTEGER PRIMARY KEY, -- id
+	name TEXT DEFAULT 'a;b'
+)`, stmts[0])
+	assert.Equal(t, `CREATE FUNCTION f() RETURNS trigger AS $body$
+BEGIN
+	RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql`, stmts[1])
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "test.db"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer db.Close()
+
+	fsys := fstest.MapFS{
+		"1_create_user.up.sql":    {Data: []byte("CREATE TABLE user (id INTEGER PRIMARY KEY, name TEXT);")},
+		"1_create_user.down.sql":  {Data: []byte("DROP TABLE user;")},
+		"2_add_user_age.up.sql":   {Data: []byte("ALTER TABLE user ADD COLUMN age INTEGER; INSERT INTO user (name, age) VALUES ('hello', 18);")},
+		"2_add_user_age.down.sql": {Data: []byte("ALTER TABLE user DROP COLUMN age;")},
+		"3_create_order.up.sql":   {Data: []byte("CREATE TABLE orders (id INTEGER PRIMARY KEY);")},
+		"README.md":               {Data: []byte("ignored")},
+	}
+
+	m, err := New(db, "sqlite3", fsys)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// dry-run
+	dry, _ := New(db, "sqlite3", fsys, WithDryRun())
+	list, err := dry.Up(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(list))
+
+	list, err = m.UpTo(ctx, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(list))
+
+	var age int
+	assert.Nil(t, db.QueryRow("SELECT age FROM user WHERE name = 'hello'").Scan(&age))
+	assert.Equal(t, 18, age)
+
+	list, err = m.Up(ctx)
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(list)) {
+		assert.Equal(t, int64(3), list[0].Version)
+	}
+
+	// 3 无 down 文件
+	_, err = m.Down(ctx, 1)
+	assert.NotNil(t, err)
+
+	status, err := m.Status(ctx)
+	assert.Nil(t, err)
+	for _, v := range status {
+		assert.True(t, v.Applied)
+		assert.False(t, v.Modified)
+	}
+
+	// 修改已执行的迁移文件
+	fsys["1_create_user.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE user (id INTEGER);")}
+	_, err = m.Up(ctx)
+	assert.True(t, errors.Is(err, ErrChecksum))
+}
@@ -0,0 +1,205 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/noble-gase/ne/hashkit"
+)
+
+// 文件名格式：<version>_<name>.up.sql / <version>_<name>.down.sql
+var filenameRegex = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration 版本迁移
+type Migration struct {
+	// Version 版本号
+	Version int64
+	// Name 名称
+	Name string
+	// Up 升级SQL
+	Up string
+	// Down 回滚SQL
+	Down string
+	// Checksum 升级SQL的校验和（sha256）
+	Checksum string
+}
+
+// load 从 fsys 根目录加载迁移文件，按版本号升序返回
+func load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		matches := filenameRegex.FindStringSubmatch(entry.Name())
+		if len(matches) == 0 {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version(%s): %w", entry.Name(), err)
+		}
+
+		b, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		v, ok := m[version]
+		if !ok {
+			v = &Migration{
+				Version: version,
+				Name:    matches[2],
+			}
+			m[version] = v
+		}
+		if v.Name != matches[2] {
+			return nil, fmt.Errorf("duplicate version %d: %s, %s", version, v.Name, matches[2])
+		}
+
+		switch matches[3] {
+		case "up":
+			v.Up = string(b)
+			v.Checksum = hashkit.SHA256(v.Up)
+		case "down":
+			v.Down = string(b)
+		}
+	}
+
+	list := make([]*Migration, 0, len(m))
+	for _, v := range m {
+		if len(v.Up) == 0 {
+			return nil, fmt.Errorf("missing up file: %d_%s", v.Version, v.Name)
+		}
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version < list[j].Version
+	})
+	return list, nil
+}
+
+// split 将SQL脚本拆分为单条语句（忽略注释和引号、PostgreSQL $$ 内的分号）
+func split(script string) []string {
+	var (
+		stmts []string
+		start int
+		runes = []rune(script)
+		n     = len(runes)
+	)
+
+	push := func(end int) {
+		if s := trimSpace(string(runes[start:end])); len(s) != 0 {
+			stmts = append(stmts, s)
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		r := runes[i]
+		switch {
+		case r == '-' && i+1 < n && runes[i+1] == '-':
+			// 单行注释
+			for i < n && runes[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && i+1 < n && runes[i+1] == '*':
+			// 多行注释
+			i += 2
+			for i+1 < n && (runes[i] != '*' || runes[i+1] != '/') {
+				i++
+			}
+			i++
+
+		case r == '\'' || r == '"' || r == '`':
+			// 引号（'' 转义会被视为两段相邻的字符串，不影响结果）
+			i++
+			for i < n && runes[i] != r {
+				if runes[i] == '\\' && r != '"' {
+					i++
+				}
+				i++
+			}
+
+		case r == '$':
+			// PostgreSQL dollar-quoted：$tag$ ... $tag$
+			j := i + 1
+			for j < n && (runes[j] == '_' || isAlnum(runes[j])) {
+				j++
+			}
+			if j >= n || runes[j] != '$' {
+				continue
+			}
+			tag := string(runes[i : j+1])
+			i = j + 1
+			for i < n && !hasPrefix(runes[i:], tag) {
+				i++
+			}
+			i += len([]rune(tag)) - 1
+
+		case r == ';':
+			push(i)
+			start = i + 1
+		}
+	}
+	if start < n {
+		push(n)
+	}
+	return stmts
+}
+
+// trimSpace 去除首尾空白及开头的注释，仅包含注释时返回空
+func trimSpace(s string) string {
+	runes := []rune(s)
+	n := len(runes)
+
+	i := 0
+	for i < n {
+		switch {
+		case unicode.IsSpace(runes[i]):
+			i++
+		case runes[i] == '-' && i+1 < n && runes[i+1] == '-':
+			for i < n && runes[i] != '\n' {
+				i++
+			}
+		case runes[i] == '/' && i+1 < n && runes[i+1] == '*':
+			i += 2
+			for i+1 < n && (runes[i] != '*' || runes[i+1] != '/') {
+				i++
+			}
+			i += 2
+		default:
+			return strings.TrimRightFunc(string(runes[i:]), unicode.IsSpace)
+		}
+	}
+	return ""
+}
+
+func isAlnum(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
+
+func hasPrefix(runes []rune, prefix string) bool {
+	p := []rune(prefix)
+	if len(runes) < len(p) {
+		return false
+	}
+	for i := range p {
+		if runes[i] != p[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	. "github.com/go-jet/jet/v2/mysql"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// MaxParams 单条语句的最大参数（占位符）数量
+const MaxParams = 65535
+
+// Upsert 插入记录，主键或唯一键冲突时更新 update 指定的列（为空时更新 cols 中的所有列），返回影响的行数
+//
+// 注意：MySQL 中插入的行影响数为1，更新的行影响数为2
+//
+//	// 导入模块
+//	import (
+//		jet "github.com/go-jet/jet/v2/mysql"
+//		"github.com/noble-gase/ne/sqlkit/mysql"
+//	)
+//
+//	// 执行方法（data 为 model 或 model 切片）
+//	mysql.Upsert(ctx, db, table.Demo, table.Demo.MutableColumns, model.Demo{Name: "hello"}, jet.ColumnList{table.Demo.Name})
+func Upsert(ctx context.Context, db qrm.DB, table Table, cols ColumnList, data any, update ColumnList) (int64, error) {
+	stmt := table.INSERT(cols)
+	if reflect.Indirect(reflect.ValueOf(data)).Kind() == reflect.Slice {
+		stmt = stmt.MODELS(data)
+	} else {
+		stmt = stmt.MODEL(data)
+	}
+
+	if len(update) == 0 {
+		update = cols
+	}
+	return exec(ctx, db, stmt.ON_DUPLICATE_KEY_UPDATE(onDuplicate(update)...))
+}
+
+type bulkOptions struct {
+	batchSize int
+	maxParams int
+	ignore    bool
+	update    ColumnList
+}
+
+// BulkOption 批量插入选项
+type BulkOption func(o *bulkOptions)
+
+// WithBatchSize 设置每批插入的最大行数，默认：1000
+func WithBatchSize(n int) BulkOption {
+	return func(o *bulkOptions) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithMaxParams 设置单条语句的最大参数数量，默认：65535
+func WithMaxParams(n int) BulkOption {
+	return func(o *bulkOptions) {
+		if n > 0 {
+			o.maxParams = n
+		}
+	}
+}
+
+// WithIgnore 忽略主键或唯一键冲突的记录（insert-or-ignore）
+func WithIgnore() BulkOption {
+	return func(o *bulkOptions) {
+		o.ignore = true
+	}
+}
+
+// WithUpsert 主键或唯一键冲突时更新指定的列（为空时更新所有插入列）
+func WithUpsert(update ...Column) BulkOption {
+	return func(o *bulkOptions) {
+		o.update = update
+		if o.update == nil {
+			o.update = ColumnList{}
+		}
+	}
+}
+
+// BulkInsert 批量插入记录，按行数和参数数量限制分批执行，返回影响的总行数；
+// db 为 *sql.DB 时所有批次在同一个事务中执行（ctx 中存在事务时通过 SAVEPOINT 加入），否则直接使用 db（如：*sql.Tx）
+//
+//	// 导入模块
+//	import (
+//		"github.com/noble-gase/ne/sqlkit/mysql"
+//	)
+//
+//	// 执行方法
+//	mysql.BulkInsert(ctx, db, table.Demo, table.Demo.MutableColumns, []*model.Demo{...}, mysql.WithBatchSize(500))
+func BulkInsert[T any](ctx context.Context, db qrm.DB, table Table, cols ColumnList, rows []T, opts ...BulkOption) (int64, error) {
+	o := &bulkOptions{
+		batchSize: 1000,
+		maxParams: MaxParams,
+	}
+	for _, f := range opts {
+		f(o)
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if len(cols) == 0 {
+		return 0, fmt.Errorf("bulk insert: missing columns")
+	}
+
+	size := min(o.batchSize, max(o.maxParams/len(cols), 1))
+
+	var onDuplicateKey []ColumnAssigment
+	switch {
+	case o.update != nil:
+		update := o.update
+		if len(update) == 0 {
+			update = cols
+		}
+		onDuplicateKey = onDuplicate(update)
+	case o.ignore:
+		// 冲突时赋值为自身，不产生实际更新
+		onDuplicateKey = []ColumnAssigment{
+			StringColumn(cols[0].Name()).SET(StringExp(Raw(quote(cols[0].Name())))),
+		}
+	}
+
+	run := func(db qrm.DB) (int64, error) {
+		var total int64
+		for i := 0; i < len(rows); i += size {
+			stmt := table.INSERT(cols).MODELS(rows[i:min(i+size, len(rows))])
+			if len(onDuplicateKey) != 0 {
+				stmt = stmt.ON_DUPLICATE_KEY_UPDATE(onDuplicateKey...)
+			}
+			n, err := exec(ctx, db, stmt)
+			if err != nil {
+				return 0, err
+			}
+			total += n
+		}
+		return total, nil
+	}
+
+	sqlDB, ok := db.(*sql.DB)
+	if !ok {
+		return run(db)
+	}
+
+	// 加入 ctx 中的事务（见 sqlkit.Transaction），不存在时开启新事务
+	var total int64
+	err := sqlkit.Transaction(ctx, sqlDB, func(ctx context.Context, tx *sql.Tx) (err error) {
+		total, err = run(tx)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// onDuplicate 生成 ON DUPLICATE KEY UPDATE 赋值：col = VALUES(col)
+func onDuplicate(cols ColumnList) []ColumnAssigment {
+	assigments := make([]ColumnAssigment, 0, len(cols))
+	for _, c := range cols {
+		assigments = append(assigments, StringColumn(c.Name()).SET(StringExp(Raw("VALUES("+quote(c.Name())+")"))))
+	}
+	return assigments
+}
+
+func quote(name string) string {
+	return "`" + name + "`"
+}
+
+func exec(ctx context.Context, db qrm.DB, stmt Statement) (int64, error) {
+	ret, err := builder.Exec(ctx, db, stmt)
+	if err != nil {
+		return 0, err
+	}
+	rows, _ := ret.RowsAffected()
+	return rows, nil
+}
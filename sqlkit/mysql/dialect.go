@@ -0,0 +1,71 @@
+package mysql
+
+import (
+	. "github.com/go-jet/jet/v2/mysql"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// DialectOption 方言选项
+type DialectOption = builder.DialectOption[Table, statements]
+
+// WithConvention 应用表约定：查询、统计和更新排除已软删除的记录，删除改为软删除（见 Convention.Delete），
+// 插入和更新自动填充审计列，更新时校验并递增版本（见 Convention.Update）
+func WithConvention(c *Convention) DialectOption {
+	return builder.WithConvention(c)
+}
+
+// NewDialect 返回绑定数据表的 MySQL 方言，用于 sqlkit.Repo；插入后通过 LastInsertId 回查记录
+//
+//	mysql.NewDialect(table.Demo, table.Demo.ID, table.Demo.AllColumns, table.Demo.MutableColumns)
+func NewDialect(table Table, pk Column, all, mutable ColumnList, opts ...DialectOption) sqlkit.Dialect {
+	return builder.NewDialect(table, pk, all, mutable, opts...)
+}
+
+// statements MySQL 的语句构建，见 builder.Statements
+type statements struct{}
+
+func (statements) Name() string {
+	return "mysql"
+}
+
+func (statements) Returning() bool {
+	return false
+}
+
+func (statements) Select(table Table, cols ColumnList, where BoolExpression, orderBy []OrderByClause, limit, offset int64) Statement {
+	stmt := SELECT(cols).FROM(table)
+	if where != nil {
+		stmt = stmt.WHERE(where)
+	}
+	if len(orderBy) != 0 {
+		stmt = stmt.ORDER_BY(orderBy...)
+	}
+	if limit > 0 {
+		stmt = stmt.LIMIT(limit)
+	}
+	if offset > 0 {
+		stmt = stmt.OFFSET(offset)
+	}
+	return stmt
+}
+
+func (statements) Count(table Table, where BoolExpression) Statement {
+	stmt := SELECT(COUNT(STAR).AS("count")).FROM(table)
+	if where != nil {
+		stmt = stmt.WHERE(where)
+	}
+	return stmt
+}
+
+func (statements) Insert(table Table, cols ColumnList, vals []any, returning ColumnList) Statement {
+	return table.INSERT(cols).VALUES(vals[0], vals[1:]...)
+}
+
+func (statements) Update(table Table, cols ColumnList, vals []any, where BoolExpression) Statement {
+	return table.UPDATE(cols).SET(vals[0], vals[1:]...).WHERE(where)
+}
+
+func (statements) Delete(table Table, where BoolExpression) Statement {
+	return table.DELETE().WHERE(where)
+}
@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	. "github.com/go-jet/jet/v2/mysql"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/internal"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// Page 分页结果
+type Page[T any] = sqlkit.Page[T]
+
+// PageOption 分页选项
+type PageOption = builder.PageOption
+
+// WithoutCount 不统计总数，仅通过多查一条记录判断是否有下一页
+func WithoutCount() PageOption {
+	return builder.WithoutCount()
+}
+
+// WithParallel 并发执行统计和数据查询（db 为 *sql.Tx 时无效）
+func WithParallel() PageOption {
+	return builder.WithParallel()
+}
+
+// WithEstimatedCount 使用 EXPLAIN 估算总数，适用于超大表
+func WithEstimatedCount() PageOption {
+	return builder.WithEstimatedCount()
+}
+
+// Paginate 分页查询；不会自动排除已软删除的记录，需以 Convention.Scope 包装条件（或使用绑定 WithConvention 的 sqlkit.Repo）
+//
+//	// 导入模块
+//	import (
+//		jet "github.com/go-jet/jet/v2/mysql"
+//		"github.com/noble-gase/ne/sqlkit/mysql"
+//	)
+//
+//	// 执行方法
+//	mysql.Paginate[*model.Demo](ctx, db, func(query jet.SelectStatement) jet.SelectStatement {
+//		return query.FROM(table.Demo.Table).WHERE(table.Demo.Name.LIKE(jet.String("%hello%")))
+//	}, page, size, table.Demo.AllColumns, []jet.OrderByClause{table.Demo.ID.DESC()})
+//
+//	// 不统计总数
+//	mysql.Paginate[*model.Demo](ctx, db, fn, page, size, table.Demo.AllColumns, orderBy, mysql.WithoutCount())
+func Paginate[T any](ctx context.Context, db qrm.DB, fn func(query SelectStatement) SelectStatement, page, size int, cols ColumnList, orderBy []OrderByClause, opts ...PageOption) (*Page[T], error) {
+	return builder.Paginate[T](ctx, db, builder.Pager{
+		Select: func(limit, offset int64) Statement {
+			return fn(SELECT(cols)).ORDER_BY(orderBy...).LIMIT(limit).OFFSET(offset)
+		},
+		Count: fn(SELECT(COUNT(STAR).AS("count"))),
+		Estimate: func(ctx context.Context, db qrm.DB) (int64, error) {
+			return estimate(ctx, db, fn(SELECT(cols)))
+		},
+	}, page, size, opts...)
+}
+
+// estimate 通过 EXPLAIN 估算查询的记录数（rows * filtered%）
+func estimate(ctx context.Context, db qrm.DB, stmt SelectStatement) (int64, error) {
+	query, args := stmt.Sql()
+
+	var (
+		total int64
+		err   error
+	)
+
+	start := time.Now()
+	defer func() {
+		internal.LogSQL(ctx, "EXPLAIN "+query, args, time.Since(start), -1, err)
+	}()
+
+	rows, err := db.QueryContext(ctx, "EXPLAIN "+query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		err = rows.Err()
+		return 0, err
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err = rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+
+	filtered := 100.0
+	for i, c := range columns {
+		switch c {
+		case "rows":
+			total, _ = strconv.ParseInt(values[i].String, 10, 64)
+		case "filtered":
+			if v, e := strconv.ParseFloat(values[i].String, 64); e == nil {
+				filtered = v
+			}
+		}
+	}
+	return int64(float64(total) * filtered / 100), nil
+}
@@ -0,0 +1,115 @@
+package sqlkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+type txOptions struct {
+	tx        *sql.TxOptions
+	attempts  int
+	backoff   time.Duration
+	retryable func(err error) bool
+
+	// 以下仅用于 TransactionX
+	order      []string
+	compensate map[string]func(ctx context.Context) error
+	twoPhase   bool
+}
+
+// TxOption 事务选项
+type TxOption func(o *txOptions)
+
+// WithTxOptions 设置事务的隔离级别等选项
+func WithTxOptions(opt *sql.TxOptions) TxOption {
+	return func(o *txOptions) {
+		o.tx = opt
+	}
+}
+
+// WithRetry 事务因死锁、序列化失败等可重试错误失败时，以指数退避重新执行 fn
+//
+//	attempts 最大执行次数（含首次）
+//	backoff  首次重试的等待时间，之后每次翻倍
+//	retryable 自定义可重试错误的判断，默认：IsRetryable
+//
+// 注意：嵌套事务（保存点）不会重试，应由最外层事务重试
+func WithRetry(attempts int, backoff time.Duration, retryable ...func(err error) bool) TxOption {
+	return func(o *txOptions) {
+		o.attempts = attempts
+		o.backoff = backoff
+		if len(retryable) != 0 && retryable[0] != nil {
+			o.retryable = retryable[0]
+		}
+	}
+}
+
+// WithCommitOrder 设置 TransactionX 的提交顺序，未指定的库按名称排序后提交，默认：按名称排序
+func WithCommitOrder(keys ...string) TxOption {
+	return func(o *txOptions) {
+		o.order = keys
+	}
+}
+
+// WithCompensate 设置 TransactionX 中某个库的补偿操作：
+// 当后续库提交失败时，对已提交的库按提交的逆序执行补偿
+func WithCompensate(key string, fn func(ctx context.Context) error) TxOption {
+	return func(o *txOptions) {
+		if o.compensate == nil {
+			o.compensate = make(map[string]func(ctx context.Context) error)
+		}
+		o.compensate[key] = fn
+	}
+}
+
+// WithTwoPhase TransactionX 使用两阶段提交（PREPARE TRANSACTION），仅支持 PostgreSQL
+//
+// 注意：
+//  1. 需设置 max_prepared_transactions > 0
+//  2. MySQL 的 XA 事务无法与 database/sql 的 *sql.Tx 共用，故不支持
+//  3. 不可加入外层事务（嵌套调用）
+func WithTwoPhase() TxOption {
+	return func(o *txOptions) {
+		o.twoPhase = true
+	}
+}
+
+func newTxOptions(opts []TxOption) *txOptions {
+	o := &txOptions{
+		attempts:  1,
+		retryable: IsRetryable,
+	}
+	for _, f := range opts {
+		f(o)
+	}
+	return o
+}
+
+// run 执行 fn，遇到可重试错误时按指数退避重试
+func (o *txOptions) run(ctx context.Context, fn func() error) (err error) {
+	for i := 1; ; i++ {
+		start := time.Now()
+
+		err = fn()
+		if err == nil || i >= o.attempts || !o.retryable(err) {
+			return
+		}
+
+		slog.LogAttrs(ctx, slog.LevelWarn, "[sqlkit] transaction retry", slog.Int("attempt", i+1), slog.Int("max_attempts", o.attempts), slog.Duration("duration", time.Since(start)), slog.Any("error", err))
+
+		// 指数退避 + 随机抖动
+		wait := o.backoff << min(i-1, 10)
+		if wait > 0 {
+			wait += rand.N(wait/2 + 1)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w; retry: %w", err, context.Cause(ctx))
+		case <-time.After(wait):
+		}
+	}
+}
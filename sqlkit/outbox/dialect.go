@@ -0,0 +1,111 @@
+package outbox
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type dialect interface {
+	// placeholder 返回第 i 个参数的占位符（从1开始）
+	placeholder(i int) string
+	// ddl 返回建表语句
+	ddl(table string) []string
+	// lock 返回拉取待发送消息时的行锁子句
+	lock() string
+}
+
+func newDialect(driver string) (dialect, error) {
+	switch driver {
+	case "mysql":
+		return mysql{}, nil
+	case "pgx", "postgres":
+		return pgsql{}, nil
+	case "sqlite3", "sqlite":
+		return sqlite{}, nil
+	}
+	return nil, fmt.Errorf("outbox: unsupported driver(%s)", driver)
+}
+
+type mysql struct{}
+
+func (mysql) placeholder(int) string {
+	return "?"
+}
+
+func (mysql) ddl(table string) []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGINT NOT NULL AUTO_INCREMENT,
+	topic VARCHAR(255) NOT NULL,
+	payload LONGBLOB NOT NULL,
+	status TINYINT NOT NULL DEFAULT 0,
+	attempts INT NOT NULL DEFAULT 0,
+	last_error TEXT NULL,
+	available_at BIGINT NOT NULL,
+	created_at BIGINT NOT NULL,
+	sent_at BIGINT NULL,
+	PRIMARY KEY (id),
+	KEY idx_%s_pending (status, available_at)
+)`, table, table),
+	}
+}
+
+// lock MySQL 8.0+
+func (mysql) lock() string {
+	return " FOR UPDATE SKIP LOCKED"
+}
+
+type pgsql struct{}
+
+func (pgsql) placeholder(i int) string {
+	return "$" + strconv.Itoa(i)
+}
+
+func (pgsql) ddl(table string) []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGSERIAL PRIMARY KEY,
+	topic VARCHAR(255) NOT NULL,
+	payload BYTEA NOT NULL,
+	status SMALLINT NOT NULL DEFAULT 0,
+	attempts INT NOT NULL DEFAULT 0,
+	last_error TEXT NULL,
+	available_at BIGINT NOT NULL,
+	created_at BIGINT NOT NULL,
+	sent_at BIGINT NULL
+)`, table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_pending ON %s (status, available_at)", table, table),
+	}
+}
+
+func (pgsql) lock() string {
+	return " FOR UPDATE SKIP LOCKED"
+}
+
+// sqlite 单写者数据库，不支持行锁
+type sqlite struct{}
+
+func (sqlite) placeholder(int) string {
+	return "?"
+}
+
+func (sqlite) ddl(table string) []string {
+	return []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	topic TEXT NOT NULL,
+	payload BLOB NOT NULL,
+	status INTEGER NOT NULL DEFAULT 0,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NULL,
+	available_at INTEGER NOT NULL,
+	created_at INTEGER NOT NULL,
+	sent_at INTEGER NULL
+)`, table),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_pending ON %s (status, available_at)", table, table),
+	}
+}
+
+func (sqlite) lock() string {
+	return ""
+}
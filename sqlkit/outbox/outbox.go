@@ -0,0 +1,195 @@
+// Package outbox 事务性发件箱：在业务事务中写入事件，由 Relay 异步可靠地投递
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// 消息状态
+const (
+	StatusPending = 0 // 待发送
+	StatusSent    = 1 // 已发送
+	StatusDead    = 2 // 超过最大重试次数
+)
+
+// Message 发件箱消息
+type Message struct {
+	// ID 消息ID（自增），可用于消费端去重
+	ID int64
+	// Topic 主题
+	Topic string
+	// Payload 消息内容
+	Payload []byte
+	// Attempts 已投递失败的次数
+	Attempts int
+	// CreatedAt 创建时间
+	CreatedAt time.Time
+}
+
+type options struct {
+	table string
+}
+
+// Option 发件箱选项
+type Option func(o *options)
+
+// WithTable 设置发件箱表名，默认：outbox
+func WithTable(name string) Option {
+	return func(o *options) {
+		o.table = name
+	}
+}
+
+// Outbox 事务性发件箱，支持：MySQL（8.0+）、PostgreSQL、SQLite
+//
+//	ob, err := outbox.New(db, "mysql")
+//	if err != nil {
+//		return err
+//	}
+//
+//	// 业务事务中写入事件，与业务数据一同提交或回滚
+//	sqlkit.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+//		// ... 写入业务数据
+//		return ob.Enqueue(ctx, nil, "order.created", event)
+//	})
+//
+//	// 启动投递
+//	relay := ob.Relay(outbox.StreamPublisher(uc, 0), outbox.WithCloser(closekit.P1))
+//	relay.Start(ctx)
+type Outbox struct {
+	db      *sql.DB
+	dialect dialect
+	table   string
+	wake    chan struct{}
+}
+
+// New 返回一个发件箱
+func New(db *sql.DB, driver string, opts ...Option) (*Outbox, error) {
+	d, err := newDialect(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &options{
+		table: "outbox",
+	}
+	for _, f := range opts {
+		f(o)
+	}
+
+	return &Outbox{
+		db:      db,
+		dialect: d,
+		table:   o.table,
+		wake:    make(chan struct{}, 1),
+	}, nil
+}
+
+// Migrate 创建发件箱表（若不存在）
+func (o *Outbox) Migrate(ctx context.Context) error {
+	for _, stmt := range o.dialect.ddl(o.table) {
+		if _, err := exec(ctx, o.db, stmt); err != nil {
+			return fmt.Errorf("outbox: create table: %w", err)
+		}
+	}
+	return nil
+}
+
+// Enqueue 在事务 tx 中写入消息，payload 为 []byte 或 string 时原样写入，否则序列化为 JSON；
+// tx 为 nil 时使用 ctx 中的事务（见 sqlkit.Transaction），不存在时直接写入；
+// 使用 ctx 中的事务时，提交后唤醒同进程中的 Relay 立即投递，显式传入的 tx 则等待下次轮询
+func (o *Outbox) Enqueue(ctx context.Context, tx *sql.Tx, topic string, payload any) error {
+	var b []byte
+	switch v := payload.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		var err error
+		if b, err = json.Marshal(payload); err != nil {
+			return fmt.Errorf("outbox: marshal payload: %w", err)
+		}
+	}
+
+	var db execer = tx
+	if tx == nil {
+		db = sqlkit.Executor(ctx, o.db)
+	}
+
+	p := o.dialect.placeholder
+	now := time.Now().UnixMilli()
+	query := fmt.Sprintf("INSERT INTO %s (topic, payload, status, attempts, available_at, created_at) VALUES (%s, %s, %s, %s, %s, %s)",
+		o.table, p(1), p(2), p(3), p(4), p(5), p(6))
+	if _, err := exec(ctx, db, query, topic, b, StatusPending, 0, now, now); err != nil {
+		return fmt.Errorf("outbox: enqueue: %w", err)
+	}
+
+	// 显式传入的 tx 无法感知提交，提前唤醒会读不到未提交的消息
+	if tx == nil {
+		sqlkit.AfterCommit(ctx, func(context.Context) {
+			select {
+			case o.wake <- struct{}{}:
+			default:
+			}
+		})
+	}
+	return nil
+}
+
+// Purge 删除发送时间早于 before 的已发送消息，返回删除的行数
+func (o *Outbox) Purge(ctx context.Context, before time.Time) (int64, error) {
+	p := o.dialect.placeholder
+	query := fmt.Sprintf("DELETE FROM %s WHERE status = %s AND sent_at < %s", o.table, p(1), p(2))
+	ret, err := exec(ctx, o.db, query, StatusSent, before.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+	return ret.RowsAffected()
+}
+
+// Requeue 将超过最大重试次数的消息重新置为待发送
+func (o *Outbox) Requeue(ctx context.Context, ids ...int64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	p := o.dialect.placeholder
+	args := []any{StatusPending, time.Now().UnixMilli(), StatusDead}
+	in := ""
+	for i, id := range ids {
+		if i != 0 {
+			in += ", "
+		}
+		args = append(args, id)
+		in += p(len(args))
+	}
+	query := fmt.Sprintf("UPDATE %s SET status = %s, attempts = 0, available_at = %s WHERE status = %s AND id IN (%s)", o.table, p(1), p(2), p(3), in)
+	ret, err := exec(ctx, o.db, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return ret.RowsAffected()
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func exec(ctx context.Context, db execer, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	ret, err := db.ExecContext(ctx, query, args...)
+	rows := int64(-1)
+	if err == nil {
+		rows, _ = ret.RowsAffected()
+	}
+	internal.LogSQL(ctx, query, args, time.Since(start), rows, err)
+	return ret, err
+}
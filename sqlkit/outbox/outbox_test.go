@@ -0,0 +1,178 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/sqltest"
+	"github.com/stretchr/testify/assert"
+)
+
+func status(t *testing.T, db *sql.DB, id int64) (int, int) {
+	var s, attempts int
+	assert.Nil(t, db.QueryRow("SELECT status, attempts FROM outbox WHERE id = ?", id).Scan(&s, &attempts))
+	return s, attempts
+}
+
+func TestEnqueue(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqltest.SQLite(t)
+	ob, err := New(db, "sqlite3")
+	assert.Nil(t, err)
+	assert.Nil(t, ob.Migrate(ctx))
+
+	err = sqlkit.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		return ob.Enqueue(ctx, tx, "demo", map[string]int{"id": 1})
+	})
+	assert.Nil(t, err)
+
+	// 回滚的事务不写入消息
+	err = sqlkit.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		if err := ob.Enqueue(ctx, nil, "demo", "hello"); err != nil {
+			return err
+		}
+		return errors.New("oops")
+	})
+	assert.NotNil(t, err)
+
+	var payload string
+	assert.Nil(t, db.QueryRow("SELECT payload FROM outbox").Scan(&payload))
+	assert.JSONEq(t, `{"id": 1}`, payload)
+
+	_, err = New(db, "oracle")
+	assert.NotNil(t, err)
+}
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqltest.SQLite(t)
+	ob, err := New(db, "sqlite3")
+	assert.Nil(t, err)
+	assert.Nil(t, ob.Migrate(ctx))
+
+	assert.Nil(t, ob.Enqueue(ctx, nil, "ok", "a"))
+	assert.Nil(t, ob.Enqueue(ctx, nil, "fail", "b"))
+
+	var sent []string
+	relay := ob.Relay(PublisherFunc(func(ctx context.Context, msg *Message) error {
+		if msg.Topic == "fail" {
+			return errors.New("unavailable")
+		}
+		sent = append(sent, string(msg.Payload))
+		return nil
+	}), WithMaxAttempts(2), WithBackoff(0, 0))
+
+	n, err := relay.Process(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"a"}, sent)
+
+	s, attempts := status(t, db, 1)
+	assert.Equal(t, StatusSent, s)
+	assert.Equal(t, 0, attempts)
+	s, attempts = status(t, db, 2)
+	assert.Equal(t, StatusPending, s)
+	assert.Equal(t, 1, attempts)
+
+	// 达到最大投递次数
+	n, err = relay.Process(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	s, _ = status(t, db, 2)
+	assert.Equal(t, StatusDead, s)
+
+	n, err = relay.Process(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+
+	// 重新投递
+	cnt, err := ob.Requeue(ctx, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cnt)
+	s, attempts = status(t, db, 2)
+	assert.Equal(t, StatusPending, s)
+	assert.Equal(t, 0, attempts)
+
+	cnt, err = ob.Purge(ctx, time.Now().Add(time.Second))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), cnt)
+}
+
+func TestRelayStart(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqltest.SQLite(t)
+	ob, err := New(db, "sqlite3")
+	assert.Nil(t, err)
+	assert.Nil(t, ob.Migrate(ctx))
+
+	ch := make(chan int64, 1)
+	relay := ob.Relay(PublisherFunc(func(ctx context.Context, msg *Message) error {
+		ch <- msg.ID
+		return nil
+	}), WithPollInterval(time.Hour))
+	// 可重复启停
+	assert.Nil(t, relay.Start(ctx))
+	assert.Nil(t, relay.Stop())
+	assert.Nil(t, relay.Start(ctx))
+	assert.ErrorIs(t, relay.Start(ctx), ErrRelayStarted)
+	defer relay.Stop()
+
+	// 使用 ctx 中的事务，提交后立即唤醒投递
+	err = sqlkit.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		return ob.Enqueue(ctx, nil, "demo", "hello")
+	})
+	assert.Nil(t, err)
+
+	select {
+	case id := <-ch:
+		assert.Equal(t, int64(1), id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("relay not woken")
+	}
+}
+
+func TestRelayStop(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqltest.SQLite(t)
+	ob, err := New(db, "sqlite3")
+	assert.Nil(t, err)
+	assert.Nil(t, ob.Migrate(ctx))
+	assert.Nil(t, ob.Enqueue(ctx, nil, "demo", "hello"))
+
+	publishing := make(chan struct{})
+	release := make(chan struct{})
+	relay := ob.Relay(PublisherFunc(func(ctx context.Context, msg *Message) error {
+		close(publishing)
+		<-release
+		return ctx.Err()
+	}), WithPollInterval(time.Hour))
+	assert.Nil(t, relay.Start(ctx))
+
+	<-publishing
+	stopped := make(chan struct{})
+	go func() {
+		assert.Nil(t, relay.Stop())
+		close(stopped)
+	}()
+
+	// Stop 等待当前批次完成，且不取消批次的 ctx
+	select {
+	case <-stopped:
+		t.Fatal("stop returned before the batch completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-stopped
+
+	s, attempts := status(t, db, 1)
+	assert.Equal(t, StatusSent, s)
+	assert.Equal(t, 0, attempts)
+}
@@ -0,0 +1,321 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/noble-gase/ne/closekit"
+	"github.com/noble-gase/ne/sqlkit/internal"
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrRelayStarted Relay 已启动
+var ErrRelayStarted = errors.New("outbox: relay already started")
+
+// Publisher 消息发布者，返回 error 时消息将按退避策略重试；
+// 投递语义为「至少一次」，消费端应按 Message.ID 去重
+type Publisher interface {
+	Publish(ctx context.Context, msg *Message) error
+}
+
+// PublisherFunc 函数形式的 Publisher
+type PublisherFunc func(ctx context.Context, msg *Message) error
+
+func (fn PublisherFunc) Publish(ctx context.Context, msg *Message) error {
+	return fn(ctx, msg)
+}
+
+// StreamPublisher 发布到 Redis Stream（Stream 名称为 Topic），消息字段与 redkit.Queue 兼容：
+// data 为 Payload，id 为消息ID；maxLen > 0 时近似裁剪 Stream 长度
+func StreamPublisher(uc redis.UniversalClient, maxLen int64) Publisher {
+	return PublisherFunc(func(ctx context.Context, msg *Message) error {
+		args := &redis.XAddArgs{
+			Stream: msg.Topic,
+			Values: map[string]any{
+				"data": string(msg.Payload),
+				"id":   msg.ID,
+			},
+		}
+		if maxLen > 0 {
+			args.MaxLen = maxLen
+			args.Approx = true
+		}
+		return uc.XAdd(ctx, args).Err()
+	})
+}
+
+type relayOptions struct {
+	batchSize    int
+	batchTimeout time.Duration
+	interval     time.Duration
+	maxAttempts  int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	closePrior   closekit.Priority
+	closeEnable  bool
+}
+
+// RelayOption Relay 选项
+type RelayOption func(o *relayOptions)
+
+// WithBatchSize 设置每批拉取的消息数，默认：100
+func WithBatchSize(n int) RelayOption {
+	return func(o *relayOptions) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithBatchTimeout 设置每批投递的超时时间（Stop 时会等待当前批次完成），默认：30s
+func WithBatchTimeout(d time.Duration) RelayOption {
+	return func(o *relayOptions) {
+		if d > 0 {
+			o.batchTimeout = d
+		}
+	}
+}
+
+// WithPollInterval 设置轮询间隔，默认：1s
+func WithPollInterval(d time.Duration) RelayOption {
+	return func(o *relayOptions) {
+		if d > 0 {
+			o.interval = d
+		}
+	}
+}
+
+// WithMaxAttempts 设置最大投递次数，超过后消息标记为 StatusDead，默认：16
+func WithMaxAttempts(n int) RelayOption {
+	return func(o *relayOptions) {
+		if n > 0 {
+			o.maxAttempts = n
+		}
+	}
+}
+
+// WithBackoff 设置重试的指数退避区间，默认：1s ~ 5m
+func WithBackoff(minBackoff, maxBackoff time.Duration) RelayOption {
+	return func(o *relayOptions) {
+		o.minBackoff = minBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithCloser 启动时将 Stop 注册到 closekit
+func WithCloser(px closekit.Priority) RelayOption {
+	return func(o *relayOptions) {
+		o.closePrior = px
+		o.closeEnable = true
+	}
+}
+
+// Relay 轮询发件箱并投递消息；多实例部署时通过 SKIP LOCKED 分摊消息（SQLite 除外）
+type Relay struct {
+	outbox *Outbox
+	pub    Publisher
+	opts   *relayOptions
+
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	closer sync.Once
+}
+
+// Relay 返回一个消息投递器
+func (o *Outbox) Relay(pub Publisher, opts ...RelayOption) *Relay {
+	ro := &relayOptions{
+		batchSize:    100,
+		batchTimeout: 30 * time.Second,
+		interval:     time.Second,
+		maxAttempts:  16,
+		minBackoff:   time.Second,
+		maxBackoff:   5 * time.Minute,
+	}
+	for _, f := range opts {
+		f(ro)
+	}
+	return &Relay{
+		outbox: o,
+		pub:    pub,
+		opts:   ro,
+	}
+}
+
+// Start 启动投递（非阻塞），调用 Stop 停止
+func (r *Relay) Start(ctx context.Context) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.cancel != nil {
+		return ErrRelayStarted
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.run(ctx)
+	}()
+
+	if r.opts.closeEnable {
+		r.closer.Do(func() {
+			closekit.Add("outbox-relay:"+r.outbox.table, r.opts.closePrior, r.Stop)
+		})
+	}
+	return nil
+}
+
+// Stop 停止投递，等待当前批次完成（不会中断正在执行的批次，最长等待时间见 WithBatchTimeout）
+func (r *Relay) Stop() error {
+	r.mutex.Lock()
+	cancel := r.cancel
+	r.cancel = nil
+	r.mutex.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	r.wg.Wait()
+	return nil
+}
+
+func (r *Relay) run(ctx context.Context) {
+	ticker := time.NewTicker(r.opts.interval)
+	defer ticker.Stop()
+
+	for {
+		// 满批时继续拉取，直到积压清空
+		for {
+			n, err := r.process(ctx)
+			if err != nil {
+				slog.LogAttrs(ctx, slog.LevelError, "[outbox:Relay] process failed", slog.String("table", r.outbox.table), slog.Any("error", err))
+				break
+			}
+			if n < r.opts.batchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.outbox.wake:
+		}
+	}
+}
+
+// process 在独立的 ctx 中投递一批消息：Stop 时不中断当前批次，避免已发布的消息因回滚而重复投递
+func (r *Relay) process(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.opts.batchTimeout)
+	defer cancel()
+	return r.Process(ctx)
+}
+
+// Process 投递一批到期的消息，返回本批拉取的消息数；通常由 Start 调用，也可用于定时任务或测试
+func (r *Relay) Process(ctx context.Context) (int, error) {
+	o := r.outbox
+
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	msgs, err := r.fetch(ctx, tx)
+	if err != nil {
+		return 0, err
+	}
+	if len(msgs) == 0 {
+		return 0, nil
+	}
+
+	p := o.dialect.placeholder
+	for _, msg := range msgs {
+		perr := r.pub.Publish(ctx, msg)
+		now := time.Now()
+		if perr == nil {
+			query := fmt.Sprintf("UPDATE %s SET status = %s, sent_at = %s WHERE id = %s", o.table, p(1), p(2), p(3))
+			if _, err = exec(ctx, tx, query, StatusSent, now.UnixMilli(), msg.ID); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		attempts := msg.Attempts + 1
+		status := StatusPending
+		if attempts >= r.opts.maxAttempts {
+			status = StatusDead
+			slog.LogAttrs(ctx, slog.LevelError, "[outbox:Relay] message dead", slog.String("table", o.table), slog.Int64("id", msg.ID), slog.String("topic", msg.Topic), slog.Any("error", perr))
+		} else {
+			slog.LogAttrs(ctx, slog.LevelWarn, "[outbox:Relay] publish failed", slog.String("table", o.table), slog.Int64("id", msg.ID), slog.String("topic", msg.Topic), slog.Int("attempts", attempts), slog.Any("error", perr))
+		}
+		query := fmt.Sprintf("UPDATE %s SET status = %s, attempts = %s, last_error = %s, available_at = %s WHERE id = %s", o.table, p(1), p(2), p(3), p(4), p(5))
+		if _, err = exec(ctx, tx, query, status, attempts, perr.Error(), now.Add(r.backoff(attempts)).UnixMilli(), msg.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return len(msgs), nil
+}
+
+func (r *Relay) fetch(ctx context.Context, tx *sql.Tx) (_ []*Message, err error) {
+	o := r.outbox
+	p := o.dialect.placeholder
+
+	query := fmt.Sprintf("SELECT id, topic, payload, attempts, created_at FROM %s WHERE status = %s AND available_at <= %s ORDER BY id LIMIT %d%s",
+		o.table, p(1), p(2), r.opts.batchSize, o.dialect.lock())
+	args := []any{StatusPending, time.Now().UnixMilli()}
+
+	var msgs []*Message
+
+	start := time.Now()
+	defer func() {
+		internal.LogSQL(ctx, query, args, time.Since(start), int64(len(msgs)), err)
+	}()
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			msg       = new(Message)
+			createdAt int64
+		)
+		if err = rows.Scan(&msg.ID, &msg.Topic, &msg.Payload, &msg.Attempts, &createdAt); err != nil {
+			return nil, err
+		}
+		msg.CreatedAt = time.UnixMilli(createdAt)
+		msgs = append(msgs, msg)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// backoff 第 n 次失败后的重试间隔
+func (r *Relay) backoff(n int) time.Duration {
+	d := r.opts.minBackoff
+	for i := 1; i < n && d < r.opts.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, r.opts.maxBackoff)
+}
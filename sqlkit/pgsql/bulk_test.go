@@ -0,0 +1,23 @@
+package pgsql
+
+import (
+	"context"
+	"testing"
+
+	jet "github.com/go-jet/jet/v2/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsertConflict(t *testing.T) {
+	ctx := context.Background()
+
+	id := jet.IntegerColumn("id")
+	demo := jet.NewTable("", "demo", "", id)
+
+	// 未指定冲突列时在执行前返回错误（db 不会被使用）
+	_, err := Upsert(ctx, nil, demo, jet.ColumnList{id}, struct{ ID int64 }{1}, nil, nil)
+	assert.NotNil(t, err)
+
+	_, err = BulkInsert(ctx, nil, demo, jet.ColumnList{id}, []struct{ ID int64 }{{1}}, WithUpsert(nil))
+	assert.NotNil(t, err)
+}
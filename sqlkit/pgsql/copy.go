@@ -0,0 +1,118 @@
+package pgsql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// Copier 支持 COPY 协议的连接：*pgx.Conn、pgx.Tx、*pgxpool.Pool、*pgxpool.Conn
+type Copier interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// CopyFrom 通过 COPY 协议批量导入记录，返回导入的行数；
+// model 字段与 cols 的对应规则与 jet 相同（列名 snake_case 对应字段名 CamelCase）
+//
+//	// 导入模块
+//	import (
+//		"github.com/noble-gase/ne/sqlkit"
+//		"github.com/noble-gase/ne/sqlkit/pgsql"
+//	)
+//
+//	pool, err := sqlkit.NewPgxPool(ctx, cfg)
+//
+//	// 执行方法
+//	pgsql.CopyFrom(ctx, pool, table.Demo, table.Demo.MutableColumns, []*model.Demo{...})
+func CopyFrom[T any](ctx context.Context, conn Copier, table Table, cols ColumnList, rows []T) (n int64, err error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if len(cols) == 0 {
+		return 0, fmt.Errorf("copy from: missing columns")
+	}
+
+	ident := pgx.Identifier{table.TableName()}
+	if len(table.SchemaName()) != 0 {
+		ident = pgx.Identifier{table.SchemaName(), table.TableName()}
+	}
+	names := make([]string, 0, len(cols))
+	for _, c := range cols {
+		names = append(names, c.Name())
+	}
+
+	fields, err := copyFields(reflect.TypeFor[T](), names)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	defer func() {
+		query := fmt.Sprintf("COPY %s (%s) FROM STDIN", ident.Sanitize(), strings.Join(names, ", "))
+		internal.LogSQL(ctx, query, nil, time.Since(start), n, err)
+	}()
+
+	n, err = conn.CopyFrom(ctx, ident, names, pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) {
+		v := reflect.ValueOf(rows[i])
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, fmt.Errorf("copy from: nil row at index %d", i)
+			}
+			v = v.Elem()
+		}
+
+		values := make([]any, len(fields))
+		for j, index := range fields {
+			f, err := v.FieldByIndexErr(index)
+			if err != nil || (f.Kind() == reflect.Pointer && f.IsNil()) {
+				// 嵌入的指针结构体为 nil 或字段为 nil
+				continue
+			}
+			values[j] = reflect.Indirect(f).Interface()
+		}
+		return values, nil
+	}))
+	return
+}
+
+// copyFields 返回列对应的字段索引
+func copyFields(t reflect.Type, names []string) ([][]int, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("copy from: %s is not a struct", t)
+	}
+
+	index := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		key := identifier(f.Name)
+		if _, ok := index[key]; !ok {
+			index[key] = f.Index
+		}
+	}
+
+	fields := make([][]int, 0, len(names))
+	for _, name := range names {
+		v, ok := index[identifier(name)]
+		if !ok {
+			return nil, fmt.Errorf("copy from: missing struct field for column %s", name)
+		}
+		fields = append(fields, v)
+	}
+	return fields, nil
+}
+
+// identifier 归一化标识符：user_id、UserID、UserId 均为 userid
+func identifier(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+}
@@ -0,0 +1,44 @@
+package pgsql
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type copyBase struct {
+	CreatedAt int64
+}
+
+type copyDemo struct {
+	copyBase
+	ID       int64
+	UserID   string
+	Nickname *string
+}
+
+func TestCopyFields(t *testing.T) {
+	fields, err := copyFields(reflect.TypeFor[*copyDemo](), []string{"id", "user_id", "nickname", "created_at"})
+	assert.Nil(t, err)
+	assert.Equal(t, [][]int{{1}, {2}, {3}, {0, 0}}, fields)
+
+	_, err = copyFields(reflect.TypeFor[copyDemo](), []string{"id", "age"})
+	assert.NotNil(t, err)
+
+	_, err = copyFields(reflect.TypeFor[int](), []string{"id"})
+	assert.NotNil(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	var s string
+	assert.Nil(t, decode(`{"id":1}`, &s))
+	assert.Equal(t, `{"id":1}`, s)
+
+	var v struct {
+		ID int64 `json:"id"`
+	}
+	assert.Nil(t, decode(`{"id":1}`, &v))
+	assert.Equal(t, int64(1), v.ID)
+	assert.NotNil(t, decode(`oh no`, &v))
+}
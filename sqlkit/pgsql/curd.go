@@ -2,13 +2,12 @@ package pgsql
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"time"
 
 	. "github.com/go-jet/jet/v2/postgres"
 	"github.com/go-jet/jet/v2/qrm"
 	"github.com/noble-gase/ne/sqlkit/internal"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
 )
 
 // M 用于 PostgreSQL 的 INSERT & UPDATE
@@ -45,18 +44,20 @@ func (m M) Split() (cols ColumnList, vals []any) {
 func Insert[T any](ctx context.Context, db qrm.DB, stmt InsertStatement) (T, error) {
 	var (
 		dest T
+		rows int64
 		err  error
 	)
 
 	start := time.Now()
 	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
+		internal.Log(ctx, stmt, time.Since(start), rows, err)
 	}()
 
-	err = stmt.QueryContext(ctx, db, &dest)
-	return dest, err
+	if err = stmt.QueryContext(ctx, db, &dest); err != nil {
+		return dest, err
+	}
+	rows = 1
+	return dest, nil
 }
 
 // BatchInsert 批量插入记录
@@ -88,9 +89,7 @@ func BatchInsert[T any](ctx context.Context, db qrm.DB, stmt InsertStatement) ([
 
 	start := time.Now()
 	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
+		internal.Log(ctx, stmt, time.Since(start), int64(len(dest)), err)
 	}()
 
 	err = stmt.QueryContext(ctx, db, &dest)
@@ -113,25 +112,7 @@ func BatchInsert[T any](ctx context.Context, db qrm.DB, stmt InsertStatement) ([
 //	// 执行方法
 //	pgsql.Update(ctx, db, stmt)
 func Update(ctx context.Context, db qrm.DB, stmt UpdateStatement) (int64, error) {
-	var (
-		ret sql.Result
-		err error
-	)
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	ret, err = stmt.ExecContext(ctx, db)
-	if err != nil {
-		return 0, err
-	}
-
-	rows, _ := ret.RowsAffected()
-	return rows, nil
+	return exec(ctx, db, stmt)
 }
 
 // Delete 删除记录
@@ -148,28 +129,10 @@ func Update(ctx context.Context, db qrm.DB, stmt UpdateStatement) (int64, error)
 //	// 执行方法
 //	pgsql.Delete(ctx, db, stmt)
 func Delete(ctx context.Context, db qrm.DB, stmt DeleteStatement) (int64, error) {
-	var (
-		ret sql.Result
-		err error
-	)
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	ret, err = stmt.ExecContext(ctx, db)
-	if err != nil {
-		return 0, err
-	}
-
-	rows, _ := ret.RowsAffected()
-	return rows, nil
+	return exec(ctx, db, stmt)
 }
 
-// FindOne 查询一条记录
+// FindOne 查询一条记录；不会自动排除已软删除的记录，需以 Convention.Scope 包装条件（或使用绑定 WithConvention 的 sqlkit.Repo）
 //
 // 注意：参数 T 必须为非指针类型
 //
@@ -187,30 +150,10 @@ func Delete(ctx context.Context, db qrm.DB, stmt DeleteStatement) (int64, error)
 //	// 执行方法
 //	pgsql.FindOne[model.Demo](ctx, db, stmt)
 func FindOne[T any](ctx context.Context, db qrm.DB, stmt SelectStatement) (*T, error) {
-	var (
-		dest T
-		err  error
-	)
-
-	stmt = stmt.LIMIT(1)
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
-		if errors.Is(err, qrm.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return &dest, nil
+	return builder.FindOne[T](ctx, db, stmt.LIMIT(1))
 }
 
-// FindAll 查询多条记录
+// FindAll 查询多条记录；不会自动排除已软删除的记录，需以 Convention.Scope 包装条件（或使用绑定 WithConvention 的 sqlkit.Repo）
 //
 //	// 导入模块
 //	import (
@@ -226,25 +169,10 @@ func FindOne[T any](ctx context.Context, db qrm.DB, stmt SelectStatement) (*T, e
 //	// 执行方法
 //	pgsql.FindAll[*model.Demo](ctx, db, stmt)
 func FindAll[T any](ctx context.Context, db qrm.DB, stmt SelectStatement) ([]T, error) {
-	var (
-		dest []T
-		err  error
-	)
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
-		return nil, err
-	}
-	return dest, nil
+	return builder.FindAll[T](ctx, db, stmt)
 }
 
-// Count 返回记录数
+// Count 返回记录数；不会自动排除已软删除的记录，需以 Convention.Scope 包装条件
 //
 //	// 导入模块
 //	import (
@@ -257,94 +185,5 @@ func FindAll[T any](ctx context.Context, db qrm.DB, stmt SelectStatement) ([]T,
 //		return count.FROM(table.Demo.Table).WHERE(table.Demo.Name.LIKE(jet.String("%hello%")))
 //	})
 func Count(ctx context.Context, db qrm.DB, fn func(count SelectStatement) SelectStatement) (int64, error) {
-	var (
-		total struct {
-			Count int64
-		}
-		err error
-	)
-
-	stmt := fn(SELECT(COUNT(STAR).AS("count")))
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	if err := stmt.QueryContext(ctx, db, &total); err != nil {
-		return 0, err
-	}
-	return total.Count, nil
-}
-
-// Paginate 分页查询
-//
-//	// 导入模块
-//	import (
-//		jet "github.com/go-jet/jet/v2/postgres"
-//		"github.com/noble-gase/ne/sqlkit/pgsql"
-//	)
-//
-//	// 执行方法
-//	pgsql.Paginate[*model.Demo](ctx, db, func(query jet.SelectStatement) jet.SelectStatement {
-//		return query.FROM(table.Demo.Table).WHERE(table.Demo.Name.LIKE(jet.String("%hello%")))
-//	}, page, size, table.Demo.AllColumns, table.Demo.ID.DESC())
-func Paginate[T any](ctx context.Context, db qrm.DB, fn func(query SelectStatement) SelectStatement, page, size int, cols ColumnList, orderBy ...OrderByClause) ([]T, int64, error) {
-	var (
-		total struct {
-			Count int64
-		}
-		countErr error
-	)
-
-	// 构建 count 查询
-	countStmt := fn(SELECT(COUNT(STAR).AS("count")))
-
-	countStart := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(countStmt.DebugSql()), time.Since(countStart), countErr)
-		}
-	}()
-
-	countErr = countStmt.QueryContext(ctx, db, &total)
-	if countErr != nil {
-		return nil, 0, countErr
-	}
-	if total.Count == 0 {
-		return []T{}, 0, nil
-	}
-
-	// 数据查询
-
-	var (
-		dest     []T
-		queryErr error
-	)
-
-	if page <= 0 {
-		page = 1
-	}
-	if size <= 0 {
-		size = 20
-	}
-	offset := (page - 1) * size
-
-	// 构建分页查询
-	queryStmt := fn(SELECT(cols)).ORDER_BY(orderBy...).LIMIT(int64(size)).OFFSET(int64(offset))
-
-	queryStart := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(queryStmt.DebugSql()), time.Since(queryStart), queryErr)
-		}
-	}()
-
-	queryErr = queryStmt.QueryContext(ctx, db, &dest)
-	if queryErr != nil {
-		return nil, 0, queryErr
-	}
-	return dest, total.Count, nil
+	return builder.Count(ctx, db, fn(SELECT(COUNT(STAR).AS("count"))))
 }
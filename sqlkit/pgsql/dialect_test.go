@@ -0,0 +1,45 @@
+package pgsql
+
+import (
+	"context"
+	"testing"
+
+	jet "github.com/go-jet/jet/v2/postgres"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialect(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		id      = jet.IntegerColumn("id")
+		name    = jet.StringColumn("name")
+		version = jet.IntegerColumn("version")
+		demo    = jet.NewTable("public", "demo", "", id, name, version)
+	)
+
+	type Demo struct {
+		ID      int64
+		Name    string
+		Version int64
+	}
+
+	d := NewDialect(demo, id, jet.ColumnList{id, name, version}, jet.ColumnList{name, version}, WithConvention(&Convention{Table: demo, Version: version}))
+	assert.Equal(t, "postgres", d.Name())
+
+	stmt, err := d.Insert(ctx, &Demo{Name: "hello", Version: 1})
+	if assert.Nil(t, err) {
+		query, args := stmt.Sql()
+		assert.Contains(t, query, "RETURNING demo.id AS \"demo.id\"")
+		assert.ElementsMatch(t, []any{"hello", int64(1)}, args)
+	}
+
+	stmt, versioned, err := d.Update(ctx, &Demo{Name: "world", Version: 2}, d.PrimaryKey(1))
+	if assert.Nil(t, err) {
+		assert.True(t, versioned)
+		query, args := stmt.Sql()
+		assert.Contains(t, query, "WHERE (demo.id = $")
+		assert.Contains(t, query, "(demo.version = ($")
+		assert.Contains(t, args, int64(2))
+	}
+}
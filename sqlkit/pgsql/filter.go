@@ -0,0 +1,68 @@
+package pgsql
+
+import (
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// ErrInvalidFilter 过滤参数不合法（字段、操作符、排序不在白名单内或值格式错误）
+var ErrInvalidFilter = builder.ErrInvalidFilter
+
+// Op 过滤操作符
+type Op = builder.Op
+
+const (
+	OpEq    = builder.OpEq    // 等于（默认），多个值时视为 in
+	OpNe    = builder.OpNe    // 不等于
+	OpIn    = builder.OpIn    // 包含，值以逗号分隔或重复传参
+	OpLike  = builder.OpLike  // 模糊匹配，值不含 % 时按包含匹配
+	OpGt    = builder.OpGt    // 大于
+	OpGte   = builder.OpGte   // 大于等于
+	OpLt    = builder.OpLt    // 小于
+	OpLte   = builder.OpLte   // 小于等于
+	OpRange = builder.OpRange // 闭区间，值为 min,max（任意一端可为空）
+	OpNull  = builder.OpNull  // true：IS NULL；false：IS NOT NULL
+)
+
+// FilterField 可过滤的字段
+type FilterField = builder.FilterField
+
+// Filter 声明式过滤规则，将查询参数转换为 WHERE 条件和 ORDER BY
+//
+//	// 查询参数
+//	name[like]=hello&status[in]=1,2&created_at[range]=2024-01-01,2024-12-31&deleted_at[null]=true&sort=-created_at,id
+//	// 或（适用于 protobuf 字段名）
+//	name_like=hello&status_in=1&status_in=2
+//
+//	var DemoFilter = pgsql.NewFilter(map[string]pgsql.FilterField{
+//		"name":       {Column: table.Demo.Name, Ops: []pgsql.Op{pgsql.OpEq, pgsql.OpLike}},
+//		"status":     {Column: table.Demo.Status, Ops: []pgsql.Op{pgsql.OpEq, pgsql.OpIn}},
+//		"created_at": {Column: table.Demo.CreatedAt, Ops: []pgsql.Op{pgsql.OpRange}, Sortable: true},
+//		"deleted_at": {Column: table.Demo.DeletedAt, Ops: []pgsql.Op{pgsql.OpNull}},
+//	}, table.Demo.ID.DESC())
+//
+//	where, orderBy, err := DemoFilter.Parse(r.URL.Query())
+//	if err != nil {
+//		return err
+//	}
+//	pgsql.Paginate[*model.Demo](ctx, db, func(query jet.SelectStatement) jet.SelectStatement {
+//		return query.FROM(table.Demo).WHERE(where)
+//	}, page, size, table.Demo.AllColumns, orderBy)
+type Filter = builder.Filter
+
+// NewFilter 返回过滤规则，orderBy 为未指定排序时的默认排序
+func NewFilter(fields map[string]FilterField, orderBy ...OrderByClause) *Filter {
+	return builder.NewFilter(literal, fields, orderBy...)
+}
+
+var literal = builder.Literal{
+	Int64:  Int64,
+	Float:  Float,
+	Bool:   Bool,
+	String: String,
+
+	Int64s:  Int64s,
+	Floats:  Floats,
+	Bools:   Bools,
+	Strings: Strings,
+}
@@ -0,0 +1,294 @@
+package pgsql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/noble-gase/ne/closekit"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+var (
+	// ErrListenerStarted 监听已启动
+	ErrListenerStarted = errors.New("pgsql: listener already started")
+	// ErrListenerStopped 监听已停止（Listener 仅可使用一次）
+	ErrListenerStopped = errors.New("pgsql: listener stopped")
+)
+
+// Notification 通知
+type Notification[T any] struct {
+	// Channel 通道名称
+	Channel string
+	// PID 发送通知的后端进程ID
+	PID uint32
+	// Payload 通知内容（T 为 string 时为原始内容，否则按 JSON 解析）
+	Payload T
+}
+
+type listenOptions struct {
+	buffer      int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	onReconnect func(ctx context.Context)
+	closePrior  closekit.Priority
+	closeEnable bool
+}
+
+// ListenOption 监听选项
+type ListenOption func(o *listenOptions)
+
+// WithBufferSize 设置通知 channel 的缓冲大小，默认：64
+func WithBufferSize(n int) ListenOption {
+	return func(o *listenOptions) {
+		if n >= 0 {
+			o.buffer = n
+		}
+	}
+}
+
+// WithReconnect 设置断线重连的退避时间（指数增长），默认：1s ~ 30s
+func WithReconnect(minBackoff, maxBackoff time.Duration) ListenOption {
+	return func(o *listenOptions) {
+		if minBackoff > 0 {
+			o.minBackoff = minBackoff
+		}
+		if maxBackoff >= o.minBackoff {
+			o.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// WithOnReconnect 设置重连成功后的回调；断线期间的通知会丢失，可在此补偿（如：全量同步）
+func WithOnReconnect(fn func(ctx context.Context)) ListenOption {
+	return func(o *listenOptions) {
+		o.onReconnect = fn
+	}
+}
+
+// WithListenCloser 启动监听时将 Stop 注册到 closekit
+func WithListenCloser(px closekit.Priority) ListenOption {
+	return func(o *listenOptions) {
+		o.closePrior = px
+		o.closeEnable = true
+	}
+}
+
+// Listener 基于 LISTEN/NOTIFY 的通知监听，使用独立连接（不占用连接池），断线自动重连
+//
+//	pool, err := sqlkit.NewPgxPool(ctx, cfg)
+//
+//	l := pgsql.NewListener[Event](pool, []string{"demo_changed"}, pgsql.WithListenCloser(closekit.P1))
+//	if err := l.Start(ctx); err != nil {
+//		return err
+//	}
+//	for v := range l.C() {
+//		fmt.Println(v.Channel, v.Payload)
+//	}
+type Listener[T any] struct {
+	config   *pgx.ConnConfig
+	channels []string
+	opts     *listenOptions
+	ch       chan Notification[T]
+
+	mutex   sync.Mutex
+	cancel  context.CancelFunc
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// NewListener 返回一个通知监听，连接配置取自 pool
+func NewListener[T any](pool *pgxpool.Pool, channels []string, opts ...ListenOption) *Listener[T] {
+	o := &listenOptions{
+		buffer:     64,
+		minBackoff: time.Second,
+		maxBackoff: 30 * time.Second,
+	}
+	for _, f := range opts {
+		f(o)
+	}
+
+	return &Listener[T]{
+		config:   pool.Config().ConnConfig.Copy(),
+		channels: channels,
+		opts:     o,
+		ch:       make(chan Notification[T], o.buffer),
+	}
+}
+
+// C 返回通知 channel，Stop 后关闭
+func (l *Listener[T]) C() <-chan Notification[T] {
+	return l.ch
+}
+
+// Start 建立连接并开始监听（非阻塞），调用 Stop 停止；Stop 后不可再次启动（返回 ErrListenerStopped）
+func (l *Listener[T]) Start(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.stopped {
+		return ErrListenerStopped
+	}
+	if l.cancel != nil {
+		return ErrListenerStarted
+	}
+
+	conn, err := l.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	l.cancel = cancel
+
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		l.run(ctx, conn)
+	}()
+
+	if l.opts.closeEnable {
+		closekit.Add("pgsql-listener:"+strings.Join(l.channels, ","), l.opts.closePrior, l.Stop)
+	}
+	return nil
+}
+
+// Stop 停止监听并关闭通知 channel
+func (l *Listener[T]) Stop() error {
+	l.mutex.Lock()
+	if l.stopped {
+		l.mutex.Unlock()
+		return nil
+	}
+	l.stopped = true
+	cancel := l.cancel
+	l.cancel = nil
+	l.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+		l.wg.Wait()
+	}
+	close(l.ch)
+	return nil
+}
+
+// connect 建立连接并订阅通道
+func (l *Listener[T]) connect(ctx context.Context) (*pgx.Conn, error) {
+	conn, err := pgx.ConnectConfig(ctx, l.config)
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range l.channels {
+		if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			_ = conn.Close(context.WithoutCancel(ctx))
+			return nil, fmt.Errorf("listen(%s): %w", channel, err)
+		}
+	}
+	return conn, nil
+}
+
+func (l *Listener[T]) run(ctx context.Context, conn *pgx.Conn) {
+	defer func() {
+		if conn != nil {
+			_ = conn.Close(context.Background())
+		}
+	}()
+
+	backoff := l.opts.minBackoff
+	for {
+		// 断线重连
+		if conn == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			var err error
+			if conn, err = l.connect(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.LogAttrs(ctx, slog.LevelError, "[pgsql:Listener] reconnect failed", slog.Any("channels", l.channels), slog.Any("error", err))
+				backoff = min(backoff*2, l.opts.maxBackoff)
+				continue
+			}
+			backoff = l.opts.minBackoff
+			if l.opts.onReconnect != nil {
+				l.opts.onReconnect(ctx)
+			}
+		}
+
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			slog.LogAttrs(ctx, slog.LevelError, "[pgsql:Listener] connection lost", slog.Any("channels", l.channels), slog.Any("error", err))
+			_ = conn.Close(context.Background())
+			conn = nil
+			continue
+		}
+
+		v := Notification[T]{
+			Channel: n.Channel,
+			PID:     n.PID,
+		}
+		if err = decode(n.Payload, &v.Payload); err != nil {
+			slog.LogAttrs(ctx, slog.LevelError, "[pgsql:Listener] decode payload failed", slog.String("channel", n.Channel), slog.String("payload", n.Payload), slog.Any("error", err))
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case l.ch <- v:
+		}
+	}
+}
+
+// decode 解析通知内容，dest 为 *string 时直接赋值
+func decode(payload string, dest any) error {
+	if s, ok := dest.(*string); ok {
+		*s = payload
+		return nil
+	}
+	return json.Unmarshal([]byte(payload), dest)
+}
+
+// Notify 发送通知，payload 为 string 时原样发送，否则序列化为 JSON（最大 8000 字节）
+func Notify(ctx context.Context, db qrm.DB, channel string, payload any) (err error) {
+	var s string
+	switch v := payload.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		s = string(b)
+	}
+
+	query := "SELECT pg_notify($1, $2)"
+	args := []any{channel, s}
+
+	start := time.Now()
+	defer func() {
+		internal.LogSQL(ctx, query, args, time.Since(start), -1, err)
+	}()
+
+	_, err = db.ExecContext(ctx, query, args...)
+	return
+}
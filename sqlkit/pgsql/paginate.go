@@ -0,0 +1,140 @@
+package pgsql
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/internal"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// Page 分页结果
+type Page[T any] = sqlkit.Page[T]
+
+// PageOption 分页选项
+type PageOption = builder.PageOption
+
+// WithoutCount 不统计总数，仅通过多查一条记录判断是否有下一页
+func WithoutCount() PageOption {
+	return builder.WithoutCount()
+}
+
+// WithParallel 并发执行统计和数据查询（db 为 *sql.Tx 时无效）
+func WithParallel() PageOption {
+	return builder.WithParallel()
+}
+
+// WithEstimatedCount 使用 EXPLAIN 估算总数，适用于超大表
+func WithEstimatedCount() PageOption {
+	return builder.WithEstimatedCount()
+}
+
+// Paginate 分页查询；不会自动排除已软删除的记录，需以 Convention.Scope 包装条件（或使用绑定 WithConvention 的 sqlkit.Repo）
+//
+//	// 导入模块
+//	import (
+//		jet "github.com/go-jet/jet/v2/postgres"
+//		"github.com/noble-gase/ne/sqlkit/pgsql"
+//	)
+//
+//	// 执行方法
+//	pgsql.Paginate[*model.Demo](ctx, db, func(query jet.SelectStatement) jet.SelectStatement {
+//		return query.FROM(table.Demo.Table).WHERE(table.Demo.Name.LIKE(jet.String("%hello%")))
+//	}, page, size, table.Demo.AllColumns, []jet.OrderByClause{table.Demo.ID.DESC()})
+//
+//	// 不统计总数
+//	pgsql.Paginate[*model.Demo](ctx, db, fn, page, size, table.Demo.AllColumns, orderBy, pgsql.WithoutCount())
+func Paginate[T any](ctx context.Context, db qrm.DB, fn func(query SelectStatement) SelectStatement, page, size int, cols ColumnList, orderBy []OrderByClause, opts ...PageOption) (*Page[T], error) {
+	return builder.Paginate[T](ctx, db, builder.Pager{
+		Select: func(limit, offset int64) Statement {
+			return fn(SELECT(cols)).ORDER_BY(orderBy...).LIMIT(limit).OFFSET(offset)
+		},
+		Count: fn(SELECT(COUNT(STAR).AS("count"))),
+		Estimate: func(ctx context.Context, db qrm.DB) (int64, error) {
+			return estimate(ctx, db, fn(SELECT(cols)))
+		},
+	}, page, size, opts...)
+}
+
+// estimate 通过 EXPLAIN 估算查询的记录数（执行计划的 Plan Rows）
+func estimate(ctx context.Context, db qrm.DB, stmt SelectStatement) (int64, error) {
+	query, args := stmt.Sql()
+
+	var (
+		plan []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		err error
+	)
+
+	start := time.Now()
+	defer func() {
+		internal.LogSQL(ctx, "EXPLAIN (FORMAT JSON) "+query, args, time.Since(start), -1, err)
+	}()
+
+	rows, err := db.QueryContext(ctx, "EXPLAIN (FORMAT JSON) "+query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		err = rows.Err()
+		return 0, err
+	}
+
+	var b []byte
+	if err = rows.Scan(&b); err != nil {
+		return 0, err
+	}
+	if err = json.Unmarshal(b, &plan); err != nil {
+		return 0, err
+	}
+	if len(plan) == 0 {
+		return 0, nil
+	}
+	return int64(plan[0].Plan.Rows), nil
+}
+
+// EstimateCount 通过 pg_class.reltuples 估算整表的记录数（依赖 ANALYZE/VACUUM 更新的统计信息），适用于超大表的无条件计数
+//
+//	pgsql.EstimateCount(ctx, db, "public.demo")
+func EstimateCount(ctx context.Context, db qrm.DB, table string) (int64, error) {
+	var (
+		total float64
+		err   error
+	)
+
+	query := "SELECT reltuples FROM pg_class WHERE oid = $1::regclass"
+
+	start := time.Now()
+	defer func() {
+		internal.LogSQL(ctx, query, []any{table}, time.Since(start), 1, err)
+	}()
+
+	rows, err := db.QueryContext(ctx, query, table)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err = rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+	// 从未 ANALYZE 的表 reltuples 为 -1
+	if total < 0 {
+		return 0, nil
+	}
+	return int64(total), nil
+}
@@ -0,0 +1,101 @@
+package sqlkit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// NewPgxPool 返回原生的 pgx 连接池，用于 COPY、LISTEN/NOTIFY 等 database/sql 不支持的特性；
+// 查询日志同样通过 SetLogger 输出
+//
+//	MaxOpenConns    -> MaxConns
+//	ConnMaxIdleTime -> MaxConnIdleTime
+//	ConnMaxLifetime -> MaxConnLifetime
+//	MaxIdleConns    无对应选项，忽略
+func NewPgxPool(ctx context.Context, cfg *Config) (*pgxpool.Pool, error) {
+	c := *cfg
+	if len(c.Driver) == 0 {
+		c.Driver = "pgx"
+	}
+	if c.Driver != "pgx" && c.Driver != "postgres" {
+		return nil, fmt.Errorf("pgxpool: unsupported driver %q", c.Driver)
+	}
+
+	dsn, err := c.BuildDSN()
+	if err != nil {
+		return nil, err
+	}
+	idleTime, err := c.Options.ConnMaxIdleTime.Value()
+	if err != nil {
+		return nil, fmt.Errorf("conn_max_idle_time: %w", err)
+	}
+	lifetime, err := c.Options.ConnMaxLifetime.Value()
+	if err != nil {
+		return nil, fmt.Errorf("conn_max_lifetime: %w", err)
+	}
+
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if c.Options.MaxOpenConns > 0 {
+		poolCfg.MaxConns = int32(c.Options.MaxOpenConns)
+	}
+	if idleTime > 0 {
+		poolCfg.MaxConnIdleTime = idleTime
+	}
+	if lifetime > 0 {
+		poolCfg.MaxConnLifetime = lifetime
+	}
+	poolCfg.ConnConfig.Tracer = tracer{}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, err
+	}
+	if err = pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return pool, nil
+}
+
+type traceKey struct{}
+
+type traceData struct {
+	sql   string
+	args  []any
+	start time.Time
+}
+
+// tracer 通过 internal.LogSQL 输出 pgx 的查询日志
+type tracer struct{}
+
+func (tracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if internal.Logger == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, traceKey{}, &traceData{
+		sql:   data.SQL,
+		args:  data.Args,
+		start: time.Now(),
+	})
+}
+
+func (tracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	v, ok := ctx.Value(traceKey{}).(*traceData)
+	if !ok {
+		return
+	}
+
+	rows := int64(-1)
+	if data.Err == nil {
+		rows = data.CommandTag.RowsAffected()
+	}
+	internal.LogSQL(ctx, v.sql, v.args, time.Since(v.start), rows, data.Err)
+}
@@ -0,0 +1,153 @@
+package sqlkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	// jet 各方言的表达式、语句等类型均为同一类型的别名，此处仅引用类型
+	jet "github.com/go-jet/jet/v2/mysql"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// Dialect 数据库方言，绑定数据表并构建对应方言的语句，
+// 由 mysql.NewDialect、pgsql.NewDialect、sqlite.NewDialect 创建
+type Dialect interface {
+	// Name 方言名称：mysql | postgres | sqlite
+	Name() string
+	// Returning 是否通过 INSERT ... RETURNING 返回插入的记录（否则通过 LastInsertId 回查）
+	Returning() bool
+	// PrimaryKey 返回自增主键等于 id 的条件（用于通过 LastInsertId 回查）
+	PrimaryKey(id int64) jet.BoolExpression
+	// Select 查询语句，where 为 nil 表示无条件，limit <= 0 表示不限制
+	Select(where jet.BoolExpression, orderBy []jet.OrderByClause, limit, offset int64) jet.Statement
+	// Count 统计语句
+	Count(where jet.BoolExpression) jet.Statement
+	// Insert 插入语句（启用表约定时自动填充审计列，ctx 用于获取操作人）
+	Insert(ctx context.Context, model any) (jet.Statement, error)
+	// Update 更新语句（更新可变列）；启用乐观锁时 versioned 为 true，未更新到记录视为版本冲突
+	Update(ctx context.Context, model any, where jet.BoolExpression) (stmt jet.Statement, versioned bool, err error)
+	// Delete 删除语句（启用软删除时为更新语句，ctx 用于获取操作人）
+	Delete(ctx context.Context, where jet.BoolExpression) jet.Statement
+}
+
+// Page 分页结果
+type Page[T any] = builder.Page[T]
+
+// Repo 基于 Dialect 的通用数据仓库，切换数据库时只需替换 Dialect；
+// db 可为 *sql.DB、*Cluster、*ShardRouter 等，ctx 中存在事务（见 Transaction）时在该事务中执行
+//
+//	repo := sqlkit.NewRepo[model.Demo](db, mysql.NewDialect(table.Demo, table.Demo.ID, table.Demo.AllColumns, table.Demo.MutableColumns))
+//
+//	demo, err := repo.Create(ctx, &model.Demo{Name: "hello"})
+//	list, err := repo.FindAll(ctx, table.Demo.Name.LIKE(jet.String("%hello%")), table.Demo.ID.DESC())
+type Repo[T any] struct {
+	db      qrm.DB
+	dialect Dialect
+}
+
+// NewRepo 返回一个数据仓库
+func NewRepo[T any](db qrm.DB, dialect Dialect) *Repo[T] {
+	return &Repo[T]{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// Dialect 返回数据库方言
+func (r *Repo[T]) Dialect() Dialect {
+	return r.dialect
+}
+
+// Create 插入记录并返回插入后的记录（包含自增主键、数据库默认值等）；
+// 通过 LastInsertId 回查时，若主键非自增（LastInsertId 为 0）则返回 model
+func (r *Repo[T]) Create(ctx context.Context, model *T) (*T, error) {
+	db := r.executor(ctx)
+
+	stmt, err := r.dialect.Insert(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.dialect.Returning() {
+		return builder.FindOne[T](ctx, db, stmt)
+	}
+
+	ret, err := builder.Exec(ctx, db, stmt)
+	if err != nil {
+		return nil, err
+	}
+	id, err := ret.LastInsertId()
+	if err != nil || id == 0 {
+		return model, nil
+	}
+	return r.FindOne(ctx, r.dialect.PrimaryKey(id))
+}
+
+// FindOne 查询一条记录，不存在时返回 nil
+func (r *Repo[T]) FindOne(ctx context.Context, where jet.BoolExpression) (*T, error) {
+	return builder.FindOne[T](ctx, r.executor(ctx), r.dialect.Select(where, nil, 1, 0))
+}
+
+// FindAll 查询多条记录
+func (r *Repo[T]) FindAll(ctx context.Context, where jet.BoolExpression, orderBy ...jet.OrderByClause) ([]T, error) {
+	return builder.FindAll[T](ctx, r.executor(ctx), r.dialect.Select(where, orderBy, 0, 0))
+}
+
+// Count 返回记录数
+func (r *Repo[T]) Count(ctx context.Context, where jet.BoolExpression) (int64, error) {
+	return builder.Count(ctx, r.executor(ctx), r.dialect.Count(where))
+}
+
+// Paginate 分页查询，同各方言的 Paginate（不支持分页选项）
+func (r *Repo[T]) Paginate(ctx context.Context, where jet.BoolExpression, page, size int, orderBy ...jet.OrderByClause) (*Page[T], error) {
+	return builder.Paginate[T](ctx, r.executor(ctx), builder.Pager{
+		Select: func(limit, offset int64) jet.Statement {
+			return r.dialect.Select(where, orderBy, limit, offset)
+		},
+		Count: r.dialect.Count(where),
+	}, page, size)
+}
+
+// Update 更新记录的可变列，返回影响的行数；启用乐观锁时以 model 中的版本校验并加1，未更新到记录时返回 ErrStaleVersion
+func (r *Repo[T]) Update(ctx context.Context, model *T, where jet.BoolExpression) (int64, error) {
+	if where == nil {
+		return 0, errors.New("update without where")
+	}
+
+	stmt, versioned, err := r.dialect.Update(ctx, model, where)
+	if err != nil {
+		return 0, err
+	}
+	ret, err := builder.Exec(ctx, r.executor(ctx), stmt)
+	if err != nil {
+		return 0, err
+	}
+	rows, _ := ret.RowsAffected()
+	if versioned && rows == 0 {
+		return 0, ErrStaleVersion
+	}
+	return rows, nil
+}
+
+// Delete 删除记录，返回影响的行数
+func (r *Repo[T]) Delete(ctx context.Context, where jet.BoolExpression) (int64, error) {
+	if where == nil {
+		return 0, errors.New("delete without where")
+	}
+	ret, err := builder.Exec(ctx, r.executor(ctx), r.dialect.Delete(ctx, where))
+	if err != nil {
+		return 0, err
+	}
+	rows, _ := ret.RowsAffected()
+	return rows, nil
+}
+
+// executor 返回 ctx 中的事务（*Cluster、*ShardRouter 自行处理事务），不存在时返回 db 本身
+func (r *Repo[T]) executor(ctx context.Context) qrm.DB {
+	if db, ok := r.db.(*sql.DB); ok {
+		return Executor(ctx, db)
+	}
+	return r.db
+}
@@ -0,0 +1,446 @@
+package sqlkit
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/go-jet/jet/v2/qrm"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	// ErrShardNotFound 分片不存在
+	ErrShardNotFound = errors.New("sqlkit: shard not found")
+	// ErrNoTenant ctx 中未设置租户ID
+	ErrNoTenant = errors.New("sqlkit: no tenant in context")
+)
+
+// ShardStrategy 分片策略，根据分片键返回分片名称
+type ShardStrategy interface {
+	Shard(key any) (string, error)
+}
+
+// ShardRange 范围分片：[From, To)
+type ShardRange struct {
+	From  int64  `json:"from" mapstructure:"from"`
+	To    int64  `json:"to" mapstructure:"to"`
+	Shard string `json:"shard" mapstructure:"shard"`
+}
+
+type rangeStrategy struct {
+	ranges []ShardRange
+}
+
+// RangeStrategy 按整数分片键的范围分片（如：用户ID 0~1000w 位于 shard-0），分片键为整数或整数字符串
+func RangeStrategy(ranges ...ShardRange) ShardStrategy {
+	ranges = slices.Clone(ranges)
+	slices.SortFunc(ranges, func(a, b ShardRange) int {
+		return cmp.Compare(a.From, b.From)
+	})
+	return &rangeStrategy{ranges: ranges}
+}
+
+func (s *rangeStrategy) Shard(key any) (string, error) {
+	n, err := shardInt(key)
+	if err != nil {
+		return "", err
+	}
+	i, _ := slices.BinarySearchFunc(s.ranges, n, func(r ShardRange, n int64) int {
+		if n < r.From {
+			return 1
+		}
+		if n >= r.To {
+			return -1
+		}
+		return 0
+	})
+	if i < len(s.ranges) && n >= s.ranges[i].From && n < s.ranges[i].To {
+		return s.ranges[i].Shard, nil
+	}
+	return "", fmt.Errorf("%w: key %d out of range", ErrShardNotFound, n)
+}
+
+type hashStrategy struct {
+	ring   []uint32
+	shards map[uint32]string
+}
+
+// HashStrategy 一致性哈希分片，replicas 为每个分片的虚拟节点数（默认：160）；
+// 增减分片时仅迁移相邻区间的数据
+func HashStrategy(shards []string, replicas int) ShardStrategy {
+	if replicas <= 0 {
+		replicas = 160
+	}
+
+	s := &hashStrategy{
+		ring:   make([]uint32, 0, len(shards)*replicas),
+		shards: make(map[uint32]string, len(shards)*replicas),
+	}
+	for _, name := range shards {
+		for i := 0; i < replicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(name + "#" + strconv.Itoa(i)))
+			if _, ok := s.shards[h]; ok {
+				continue
+			}
+			s.ring = append(s.ring, h)
+			s.shards[h] = name
+		}
+	}
+	slices.Sort(s.ring)
+	return s
+}
+
+func (s *hashStrategy) Shard(key any) (string, error) {
+	if len(s.ring) == 0 {
+		return "", ErrShardNotFound
+	}
+
+	h := crc32.ChecksumIEEE([]byte(shardString(key)))
+	i, _ := slices.BinarySearch(s.ring, h)
+	if i == len(s.ring) {
+		i = 0
+	}
+	return s.shards[s.ring[i]], nil
+}
+
+func shardInt(key any) (int64, error) {
+	switch v := key.(type) {
+	case int:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case uint:
+		return shardUint(uint64(v))
+	case uint32:
+		return int64(v), nil
+	case uint64:
+		return shardUint(v)
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	}
+	return 0, fmt.Errorf("sqlkit: unsupported shard key %T", key)
+}
+
+func shardUint(v uint64) (int64, error) {
+	if v > math.MaxInt64 {
+		return 0, fmt.Errorf("sqlkit: shard key %d overflows int64", v)
+	}
+	return int64(v), nil
+}
+
+func shardString(key any) string {
+	switch v := key.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	if n, err := shardInt(key); err == nil {
+		return strconv.FormatInt(n, 10)
+	}
+	return fmt.Sprint(key)
+}
+
+// ShardConfig 分片配置
+type ShardConfig struct {
+	// Shards 分片，key 为分片名称
+	Shards map[string]Config `json:"shards" mapstructure:"shards"`
+	// Tenants 租户ID到分片名称的映射（优先于分片策略）
+	Tenants map[string]string `json:"tenants" mapstructure:"tenants"`
+}
+
+type shardOptions struct {
+	strategy ShardStrategy
+	resolver func(ctx context.Context, tenant string) (string, error)
+	open     func(cfg *Config) (*sql.DB, error)
+}
+
+// ShardOption 分片路由选项
+type ShardOption func(o *shardOptions)
+
+// WithShardStrategy 设置分片策略，用于 ByKey 和未在 Tenants 中映射的租户
+func WithShardStrategy(s ShardStrategy) ShardOption {
+	return func(o *shardOptions) {
+		o.strategy = s
+	}
+}
+
+// WithTenantResolver 自定义租户ID到分片名称的解析（如：查询租户表），优先于 Tenants 和分片策略
+func WithTenantResolver(fn func(ctx context.Context, tenant string) (string, error)) ShardOption {
+	return func(o *shardOptions) {
+		o.resolver = fn
+	}
+}
+
+// WithShardOpener 自定义分片的连接方式，默认：NewDB
+func WithShardOpener(fn func(cfg *Config) (*sql.DB, error)) ShardOption {
+	return func(o *shardOptions) {
+		o.open = fn
+	}
+}
+
+type shard struct {
+	cfg   Config
+	mutex sync.Mutex
+	db    *sql.DB
+}
+
+// ShardRouter 多租户/分片数据库路由，分片在首次使用时连接，之后复用连接池
+//
+//	router := sqlkit.NewShardRouter(cfg, sqlkit.WithShardStrategy(sqlkit.HashStrategy([]string{"shard-0", "shard-1"}, 0)))
+//
+//	// 按 ctx 中的租户路由
+//	ctx = sqlkit.WithTenant(ctx, "tenant-1")
+//	db, err := router.DB(ctx)
+//
+//	// 按分片键路由
+//	db, err := router.ByKey(userID)
+//
+//	// 作为 qrm.DB 使用时按 ctx 中的租户路由（如：sqlkit.NewRepo），ctx 中存在该分片的事务时在事务中执行
+//	repo := sqlkit.NewRepo[model.Demo](router, mysql.NewDialect(table.Demo, table.Demo.ID, table.Demo.AllColumns, table.Demo.MutableColumns))
+//
+//	// 查询所有分片并合并结果
+//	list, err := sqlkit.FanOut(ctx, router, func(ctx context.Context, name string, db *sql.DB) ([]*model.Demo, error) {
+//		return mysql.FindAll[*model.Demo](ctx, db, stmt)
+//	})
+type ShardRouter struct {
+	shards   map[string]*shard
+	tenants  map[string]string
+	strategy ShardStrategy
+	resolver func(ctx context.Context, tenant string) (string, error)
+	open     func(cfg *Config) (*sql.DB, error)
+}
+
+var _ qrm.DB = (*ShardRouter)(nil)
+
+// NewShardRouter 返回一个分片路由
+func NewShardRouter(cfg *ShardConfig, opts ...ShardOption) *ShardRouter {
+	o := &shardOptions{
+		open: NewDB,
+	}
+	for _, f := range opts {
+		f(o)
+	}
+
+	r := &ShardRouter{
+		shards:   make(map[string]*shard, len(cfg.Shards)),
+		tenants:  cfg.Tenants,
+		strategy: o.strategy,
+		resolver: o.resolver,
+		open:     o.open,
+	}
+	for name, c := range cfg.Shards {
+		r.shards[name] = &shard{cfg: c}
+	}
+	return r
+}
+
+// Names 返回所有分片名称（已排序）
+func (r *ShardRouter) Names() []string {
+	names := make([]string, 0, len(r.shards))
+	for name := range r.shards {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Shard 返回指定名称的分片
+func (r *ShardRouter) Shard(name string) (*sql.DB, error) {
+	s, ok := r.shards[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrShardNotFound, name)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.db != nil {
+		return s.db, nil
+	}
+	db, err := r.open(&s.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("open shard(%s): %w", name, err)
+	}
+	s.db = db
+	return db, nil
+}
+
+// DB 返回 ctx 中租户（见 WithTenant）所在的分片
+func (r *ShardRouter) DB(ctx context.Context) (*sql.DB, error) {
+	tenant, ok := Tenant(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+
+	name, err := r.resolve(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return r.Shard(name)
+}
+
+func (r *ShardRouter) resolve(ctx context.Context, tenant string) (string, error) {
+	if r.resolver != nil {
+		return r.resolver(ctx, tenant)
+	}
+	if name, ok := r.tenants[tenant]; ok {
+		return name, nil
+	}
+	if r.strategy != nil {
+		return r.strategy.Shard(tenant)
+	}
+	return "", fmt.Errorf("%w: tenant %s", ErrShardNotFound, tenant)
+}
+
+// ByKey 根据分片策略返回分片键所在的分片
+func (r *ShardRouter) ByKey(key any) (*sql.DB, error) {
+	if r.strategy == nil {
+		return nil, errors.New("sqlkit: shard strategy not set")
+	}
+	name, err := r.strategy.Shard(key)
+	if err != nil {
+		return nil, err
+	}
+	return r.Shard(name)
+}
+
+func (r *ShardRouter) Exec(query string, args ...any) (sql.Result, error) {
+	return r.ExecContext(context.Background(), query, args...)
+}
+
+func (r *ShardRouter) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	db, err := r.DB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Executor(ctx, db).ExecContext(ctx, query, args...)
+}
+
+func (r *ShardRouter) Query(query string, args ...any) (*sql.Rows, error) {
+	return r.QueryContext(context.Background(), query, args...)
+}
+
+func (r *ShardRouter) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	db, err := r.DB(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Executor(ctx, db).QueryContext(ctx, query, args...)
+}
+
+// Close 关闭所有已连接的分片
+func (r *ShardRouter) Close() error {
+	var errs []error
+	for _, name := range r.Names() {
+		s := r.shards[name]
+
+		s.mutex.Lock()
+		if s.db != nil {
+			if err := s.db.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("close shard(%s): %w", name, err))
+			}
+			s.db = nil
+		}
+		s.mutex.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+type fanOutOptions struct {
+	shards  []string
+	limit   int
+	partial bool
+}
+
+// FanOutOption FanOut 选项
+type FanOutOption func(o *fanOutOptions)
+
+// WithShards 仅查询指定的分片，默认：所有分片
+func WithShards(names ...string) FanOutOption {
+	return func(o *fanOutOptions) {
+		o.shards = names
+	}
+}
+
+// WithConcurrency 设置并发查询的分片数，默认：不限制
+func WithConcurrency(n int) FanOutOption {
+	return func(o *fanOutOptions) {
+		o.limit = n
+	}
+}
+
+// WithPartial 部分分片失败时仍返回成功分片的结果（同时返回错误）
+func WithPartial() FanOutOption {
+	return func(o *fanOutOptions) {
+		o.partial = true
+	}
+}
+
+// FanOut 并发查询所有分片，按分片名称顺序合并结果；排序和截取见 MergeSorted
+func FanOut[T any](ctx context.Context, r *ShardRouter, fn func(ctx context.Context, name string, db *sql.DB) ([]T, error), opts ...FanOutOption) ([]T, error) {
+	o := new(fanOutOptions)
+	for _, f := range opts {
+		f(o)
+	}
+
+	names := o.shards
+	if len(names) == 0 {
+		names = r.Names()
+	}
+
+	var (
+		results = make([][]T, len(names))
+		errs    = make([]error, len(names))
+	)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	if o.partial {
+		// 单个分片失败不取消其它分片
+		eg = new(errgroup.Group)
+		egCtx = ctx
+	}
+	if o.limit > 0 {
+		eg.SetLimit(o.limit)
+	}
+	for i, name := range names {
+		eg.Go(func() error {
+			db, err := r.Shard(name)
+			if err == nil {
+				results[i], err = fn(egCtx, name, db)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("shard(%s): %w", name, err)
+			}
+			return errs[i]
+		})
+	}
+	if err := eg.Wait(); err != nil && !o.partial {
+		return nil, err
+	}
+
+	var ret []T
+	for _, v := range results {
+		ret = append(ret, v...)
+	}
+	return ret, errors.Join(errs...)
+}
+
+// MergeSorted 对 FanOut 合并的结果排序并截取前 limit 条（limit <= 0 表示不截取），用于跨分片的 ORDER BY ... LIMIT
+func MergeSorted[T any](list []T, cmp func(a, b T) int, limit int) []T {
+	slices.SortStableFunc(list, cmp)
+	if limit > 0 && len(list) > limit {
+		list = list[:limit]
+	}
+	return list
+}
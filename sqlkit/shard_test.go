@@ -0,0 +1,138 @@
+package sqlkit
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardStrategy(t *testing.T) {
+	s := RangeStrategy(
+		ShardRange{From: 100, To: 200, Shard: "shard-1"},
+		ShardRange{From: 0, To: 100, Shard: "shard-0"},
+	)
+	for key, shard := range map[any]string{0: "shard-0", int64(99): "shard-0", "100": "shard-1", uint32(199): "shard-1"} {
+		v, err := s.Shard(key)
+		assert.Nil(t, err)
+		assert.Equal(t, shard, v)
+	}
+	_, err := s.Shard(200)
+	assert.ErrorIs(t, err, ErrShardNotFound)
+	_, err = s.Shard(1.5)
+	assert.NotNil(t, err)
+	// 溢出 int64 不应回绕为负数
+	_, err = s.Shard(uint64(math.MaxUint64))
+	assert.NotNil(t, err)
+	assert.NotErrorIs(t, err, ErrShardNotFound)
+
+	h := HashStrategy([]string{"shard-0", "shard-1", "shard-2"}, 0)
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		v, err := h.Shard(i)
+		assert.Nil(t, err)
+		counts[v]++
+
+		// 相同的键总是路由到相同的分片
+		again, _ := h.Shard(fmt.Sprint(i))
+		assert.Equal(t, v, again)
+	}
+	assert.Len(t, counts, 3)
+	for _, n := range counts {
+		assert.Greater(t, n, 500)
+	}
+}
+
+func TestShardRouter(t *testing.T) {
+	dir := t.TempDir()
+
+	opened := 0
+	router := NewShardRouter(&ShardConfig{
+		Shards: map[string]Config{
+			"shard-0": {Driver: "sqlite3", DSN: "file:" + filepath.Join(dir, "shard-0.db")},
+			"shard-1": {Driver: "sqlite3", DSN: "file:" + filepath.Join(dir, "shard-1.db")},
+		},
+		Tenants: map[string]string{"tenant-a": "shard-1"},
+	}, WithShardStrategy(RangeStrategy(ShardRange{From: 0, To: 10, Shard: "shard-0"})), WithShardOpener(func(cfg *Config) (*sql.DB, error) {
+		opened++
+		return NewDB(cfg)
+	}))
+	defer router.Close()
+
+	ctx := context.Background()
+
+	_, err := router.DB(ctx)
+	assert.ErrorIs(t, err, ErrNoTenant)
+
+	db1, err := router.DB(WithTenant(ctx, "tenant-a"))
+	assert.Nil(t, err)
+	db0, err := router.ByKey(5)
+	assert.Nil(t, err)
+	again, err := router.Shard("shard-1")
+	assert.Nil(t, err)
+	assert.Same(t, db1, again)
+	assert.Equal(t, 2, opened)
+
+	_, err = router.Shard("shard-9")
+	assert.ErrorIs(t, err, ErrShardNotFound)
+
+	for i, db := range []*sql.DB{db0, db1} {
+		_, err = db.Exec("CREATE TABLE demo (id INTEGER)")
+		assert.Nil(t, err)
+		_, err = db.Exec("INSERT INTO demo (id) VALUES (?), (?)", i, i+10)
+		assert.Nil(t, err)
+	}
+
+	// 作为 qrm.DB 时按 ctx 中的租户路由，并加入该分片的事务
+	_, err = router.ExecContext(ctx, "INSERT INTO demo (id) VALUES (?)", 99)
+	assert.ErrorIs(t, err, ErrNoTenant)
+
+	errRollback := errors.New("rollback")
+	err = Transaction(WithTenant(ctx, "tenant-a"), db1, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := router.ExecContext(ctx, "INSERT INTO demo (id) VALUES (?)", 99); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+
+	var count int
+	assert.Nil(t, db1.QueryRow("SELECT COUNT(*) FROM demo").Scan(&count))
+	assert.Equal(t, 2, count)
+
+	list, err := FanOut(ctx, router, func(ctx context.Context, name string, db *sql.DB) ([]int, error) {
+		rows, err := db.QueryContext(ctx, "SELECT id FROM demo ORDER BY id DESC LIMIT 2")
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		var ids []int
+		for rows.Next() {
+			var id int
+			if err = rows.Scan(&id); err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, rows.Err()
+	}, WithConcurrency(1))
+	assert.Nil(t, err)
+	assert.Equal(t, []int{10, 0, 11, 1}, list)
+	assert.Equal(t, []int{11, 10, 1}, MergeSorted(list, func(a, b int) int { return cmp.Compare(b, a) }, 3))
+
+	list, err = FanOut(ctx, router, func(ctx context.Context, name string, db *sql.DB) ([]int, error) {
+		if name == "shard-0" {
+			return nil, sql.ErrConnDone
+		}
+		return []int{1}, nil
+	}, WithPartial())
+	assert.ErrorIs(t, err, sql.ErrConnDone)
+	assert.Equal(t, []int{1}, list)
+}
@@ -0,0 +1,196 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/go-jet/jet/v2/qrm"
+	. "github.com/go-jet/jet/v2/sqlite"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// MaxParams 单条语句的最大参数（占位符）数量，SQLite 3.32.0 之前的版本为 999，可通过 WithMaxParams 设置
+const MaxParams = 32766
+
+// Upsert 插入记录，conflict 指定的列冲突时更新 update 指定的列（为空时更新 cols 中除 conflict 外的所有列，仍为空时忽略冲突），返回影响的行数
+//
+//	// 导入模块
+//	import (
+//		jet "github.com/go-jet/jet/v2/sqlite"
+//		"github.com/noble-gase/ne/sqlkit/sqlite"
+//	)
+//
+//	// 执行方法（data 为 model 或 model 切片）
+//	sqlite.Upsert(ctx, db, table.Demo, table.Demo.MutableColumns, model.Demo{Name: "hello"}, jet.ColumnList{table.Demo.Name}, nil)
+func Upsert(ctx context.Context, db qrm.DB, table Table, cols ColumnList, data any, conflict, update ColumnList) (int64, error) {
+	stmt := table.INSERT(cols)
+	if reflect.Indirect(reflect.ValueOf(data)).Kind() == reflect.Slice {
+		stmt = stmt.MODELS(data)
+	} else {
+		stmt = stmt.MODEL(data)
+	}
+
+	if len(update) == 0 {
+		update = except(cols, conflict)
+	}
+	// 没有可更新的列（插入列均为冲突列）时忽略冲突的记录
+	if len(update) == 0 {
+		return exec(ctx, db, stmt.ON_CONFLICT(conflict...).DO_NOTHING())
+	}
+	return exec(ctx, db, stmt.ON_CONFLICT(conflict...).DO_UPDATE(SET(excluded(update)...)))
+}
+
+type bulkOptions struct {
+	batchSize int
+	maxParams int
+	ignore    bool
+	conflict  ColumnList
+	update    ColumnList
+}
+
+// BulkOption 批量插入选项
+type BulkOption func(o *bulkOptions)
+
+// WithBatchSize 设置每批插入的最大行数，默认：1000
+func WithBatchSize(n int) BulkOption {
+	return func(o *bulkOptions) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithMaxParams 设置单条语句的最大参数数量，默认：MaxParams
+func WithMaxParams(n int) BulkOption {
+	return func(o *bulkOptions) {
+		if n > 0 {
+			o.maxParams = n
+		}
+	}
+}
+
+// WithIgnore 忽略主键或唯一键冲突的记录（insert-or-ignore）
+func WithIgnore() BulkOption {
+	return func(o *bulkOptions) {
+		o.ignore = true
+	}
+}
+
+// WithUpsert conflict 指定的列冲突时更新指定的列（为空时更新除 conflict 外的所有插入列，仍为空时忽略冲突）
+func WithUpsert(conflict ColumnList, update ...Column) BulkOption {
+	return func(o *bulkOptions) {
+		o.conflict = conflict
+		o.update = update
+		if o.update == nil {
+			o.update = ColumnList{}
+		}
+	}
+}
+
+// BulkInsert 批量插入记录，按行数和参数数量限制分批执行，返回影响的总行数；
+// db 为 *sql.DB 时所有批次在同一个事务中执行（ctx 中存在事务时通过 SAVEPOINT 加入），否则直接使用 db（如：*sql.Tx）
+//
+//	// 导入模块
+//	import (
+//		"github.com/noble-gase/ne/sqlkit/sqlite"
+//	)
+//
+//	// 执行方法
+//	sqlite.BulkInsert(ctx, db, table.Demo, table.Demo.MutableColumns, []*model.Demo{...}, sqlite.WithBatchSize(500))
+func BulkInsert[T any](ctx context.Context, db qrm.DB, table Table, cols ColumnList, rows []T, opts ...BulkOption) (int64, error) {
+	o := &bulkOptions{
+		batchSize: 1000,
+		maxParams: MaxParams,
+	}
+	for _, f := range opts {
+		f(o)
+	}
+
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if len(cols) == 0 {
+		return 0, fmt.Errorf("bulk insert: missing columns")
+	}
+
+	size := min(o.batchSize, max(o.maxParams/len(cols), 1))
+
+	conflict := func(stmt InsertStatement) InsertStatement {
+		switch {
+		case o.update != nil:
+			update := o.update
+			if len(update) == 0 {
+				update = except(cols, o.conflict)
+			}
+			if len(update) == 0 {
+				return stmt.ON_CONFLICT(o.conflict...).DO_NOTHING()
+			}
+			return stmt.ON_CONFLICT(o.conflict...).DO_UPDATE(SET(excluded(update)...))
+		case o.ignore:
+			return stmt.ON_CONFLICT().DO_NOTHING()
+		}
+		return stmt
+	}
+
+	run := func(db qrm.DB) (int64, error) {
+		var total int64
+		for i := 0; i < len(rows); i += size {
+			stmt := conflict(table.INSERT(cols).MODELS(rows[i:min(i+size, len(rows))]))
+			n, err := exec(ctx, db, stmt)
+			if err != nil {
+				return 0, err
+			}
+			total += n
+		}
+		return total, nil
+	}
+
+	sqlDB, ok := db.(*sql.DB)
+	if !ok {
+		return run(db)
+	}
+
+	// 加入 ctx 中的事务（见 sqlkit.Transaction），不存在时开启新事务
+	var total int64
+	err := sqlkit.Transaction(ctx, sqlDB, func(ctx context.Context, tx *sql.Tx) (err error) {
+		total, err = run(tx)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// excluded 生成 ON CONFLICT DO UPDATE 赋值：col = excluded.col
+func excluded(cols ColumnList) []ColumnAssigment {
+	assigments := make([]ColumnAssigment, 0, len(cols))
+	for _, c := range cols {
+		assigments = append(assigments, StringColumn(c.Name()).SET(StringExp(Raw(`excluded."`+c.Name()+`"`))))
+	}
+	return assigments
+}
+
+// except 返回 cols 中除 excluded 外的列
+func except(cols, excluded ColumnList) ColumnList {
+	ret := make(ColumnList, 0, len(cols))
+	for _, c := range cols {
+		if !slices.ContainsFunc(excluded, func(v Column) bool { return v.Name() == c.Name() }) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func exec(ctx context.Context, db qrm.DB, stmt Statement) (int64, error) {
+	ret, err := builder.Exec(ctx, db, stmt)
+	if err != nil {
+		return 0, err
+	}
+	rows, _ := ret.RowsAffected()
+	return rows, nil
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	jet "github.com/go-jet/jet/v2/sqlite"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkInsert(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	rows := make([]Demo, 0, 25)
+	for i := 11; i <= 35; i++ {
+		rows = append(rows, Demo{ID: int64(i), Score: 1})
+	}
+
+	n, err := BulkInsert(ctx, db, demoTable, demoColumn, rows, WithBatchSize(10))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(25), n)
+
+	// 参数数量限制：每批 3/2=1 行
+	n, err = BulkInsert(ctx, db, demoTable, demoColumn, []Demo{{ID: 36}, {ID: 37}}, WithMaxParams(3))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), n)
+
+	_, err = BulkInsert(ctx, db, demoTable, demoColumn, []Demo{{ID: 1}, {ID: 38}})
+	assert.NotNil(t, err)
+	// 事务回滚
+	total, _ := Count(ctx, db, func(count jet.SelectStatement) jet.SelectStatement {
+		return count.FROM(demoTable)
+	})
+	assert.Equal(t, int64(37), total)
+
+	n, err = BulkInsert(ctx, db, demoTable, demoColumn, []Demo{{ID: 1}, {ID: 38}}, WithIgnore())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	_, err = BulkInsert(ctx, db, demoTable, demoColumn, []Demo{{ID: 2, Score: 100}}, WithUpsert(jet.ColumnList{demoID}))
+	assert.Nil(t, err)
+
+	_, err = Upsert(ctx, db, demoTable, demoColumn, &Demo{ID: 3, Score: 200}, jet.ColumnList{demoID}, nil)
+	assert.Nil(t, err)
+
+	// 插入列均为冲突列时忽略冲突
+	_, err = db.Exec("CREATE TABLE tag (name TEXT PRIMARY KEY)")
+	assert.Nil(t, err)
+	var (
+		tagName  = jet.StringColumn("name")
+		tagTable = jet.NewTable("", "tag", "", tagName)
+	)
+	type Tag struct {
+		Name string
+	}
+	n, err = Upsert(ctx, db, tagTable, jet.ColumnList{tagName}, &Tag{Name: "go"}, jet.ColumnList{tagName}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+	n, err = Upsert(ctx, db, tagTable, jet.ColumnList{tagName}, &Tag{Name: "go"}, jet.ColumnList{tagName}, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), n)
+	n, err = BulkInsert(ctx, db, tagTable, jet.ColumnList{tagName}, []Tag{{Name: "go"}, {Name: "sql"}}, WithUpsert(jet.ColumnList{tagName}))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), n)
+
+	list, err := FindAll[Demo](ctx, db, jet.SELECT(demoColumn).FROM(demoTable).WHERE(demoID.IN(jet.Int(2), jet.Int(3))).ORDER_BY(demoID.ASC()))
+	assert.Nil(t, err)
+	assert.Equal(t, []Demo{{ID: 2, Score: 100}, {ID: 3, Score: 200}}, list)
+
+	// 加入 ctx 中的事务，随外层事务回滚
+	err = sqlkit.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		n, err := BulkInsert(ctx, db, demoTable, demoColumn, []Demo{{ID: 50}, {ID: 51}, {ID: 52}}, WithBatchSize(1))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), n)
+		return errors.New("rollback")
+	})
+	assert.NotNil(t, err)
+	total, _ = Count(ctx, db, func(count jet.SelectStatement) jet.SelectStatement {
+		return count.FROM(demoTable)
+	})
+	assert.Equal(t, int64(38), total)
+}
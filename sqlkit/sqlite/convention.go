@@ -0,0 +1,36 @@
+package sqlite
+
+import (
+	. "github.com/go-jet/jet/v2/sqlite"
+	"github.com/noble-gase/ne/sqlkit/internal"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// ErrStaleVersion 乐观锁版本冲突（记录已被其它操作更新或不存在）
+var ErrStaleVersion = internal.ErrStaleVersion
+
+// Convention 表约定：软删除、乐观锁、审计字段，列为 nil 表示不启用
+//
+//	var DemoConv = &sqlite.Convention{
+//		Table:     table.Demo,
+//		DeletedAt: table.Demo.DeletedAt,
+//		Version:   table.Demo.Version,
+//		CreatedAt: table.Demo.CreatedAt,
+//		UpdatedAt: table.Demo.UpdatedAt,
+//		UpdatedBy: table.Demo.UpdatedBy,
+//	}
+//
+//	// 操作人通过 sqlkit.WithOperator 设置
+//	ctx = sqlkit.WithOperator(ctx, uid)
+//
+//	DemoConv.Insert(ctx, db, sqlite.M{table.Demo.Name: "hello"})
+//	DemoConv.Update(ctx, db, sqlite.M{table.Demo.Name: "world", table.Demo.Version: 1}, table.Demo.ID.EQ(jet.Int64(1)))
+//	DemoConv.Delete(ctx, db, table.Demo.ID.EQ(jet.Int64(1)))
+//
+//	// 查询排除已软删除的记录
+//	sqlite.FindAll[*model.Demo](ctx, db, table.Demo.SELECT(table.Demo.AllColumns).WHERE(DemoConv.Scope(table.Demo.Name.EQ(jet.String("hello")))))
+//
+//	// 通过 WithConvention 绑定到 sqlkit.Repo 时，查询、统计和更新自动排除已软删除的记录，删除改为软删除，
+//	// 插入和更新自动填充审计列，更新时以 model 中的版本校验并加1（冲突时返回 ErrStaleVersion）
+//	repo := sqlkit.NewRepo[model.Demo](db, sqlite.NewDialect(table.Demo, table.Demo.ID, table.Demo.AllColumns, table.Demo.MutableColumns, sqlite.WithConvention(DemoConv)))
+type Convention = builder.Convention[Table, statements]
@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	jet "github.com/go-jet/jet/v2/sqlite"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+type Article struct {
+	ID        int64 `sql:"primary_key"`
+	Title     string
+	Version   int64
+	CreatedAt int64
+	UpdatedAt int64
+	UpdatedBy *string
+	DeletedAt *int64
+}
+
+func TestConvention(t *testing.T) {
+	ctx := internal.WithOperator(context.Background(), "admin")
+	db := newTestDB(t)
+
+	_, err := db.Exec(`CREATE TABLE article (
+	id INTEGER PRIMARY KEY,
+	title TEXT NOT NULL,
+	version INTEGER NOT NULL DEFAULT 1,
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL,
+	updated_by TEXT,
+	deleted_at INTEGER
+)`)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	var (
+		id        = jet.IntegerColumn("id")
+		title     = jet.StringColumn("title")
+		version   = jet.IntegerColumn("version")
+		createdAt = jet.IntegerColumn("created_at")
+		updatedAt = jet.IntegerColumn("updated_at")
+		updatedBy = jet.StringColumn("updated_by")
+		deletedAt = jet.IntegerColumn("deleted_at")
+		article   = jet.NewTable("", "article", "", id, title, version, createdAt, updatedAt, updatedBy, deletedAt)
+		all       = jet.ColumnList{id, title, version, createdAt, updatedAt, updatedBy, deletedAt}
+	)
+
+	now := int64(100)
+	conv := &Convention{
+		Table:     article,
+		DeletedAt: deletedAt,
+		Version:   version,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		UpdatedBy: updatedBy,
+		Now:       func() any { return now },
+	}
+
+	articleID, err := conv.Insert(ctx, db, M{title: "hello"})
+	assert.Nil(t, err)
+
+	now = 200
+	rows, err := conv.Update(ctx, db, M{title: "world", version: 1}, id.EQ(jet.Int(articleID)))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), rows)
+
+	// 版本已变更
+	_, err = conv.Update(ctx, db, M{title: "stale", version: 1}, id.EQ(jet.Int(articleID)))
+	assert.ErrorIs(t, err, ErrStaleVersion)
+
+	v, err := FindOne[Article](ctx, db, jet.SELECT(all).FROM(article).WHERE(conv.Scope(id.EQ(jet.Int(articleID)))))
+	if assert.Nil(t, err) && assert.NotNil(t, v) {
+		assert.Equal(t, "world", v.Title)
+		assert.Equal(t, int64(2), v.Version)
+		assert.Equal(t, int64(100), v.CreatedAt)
+		assert.Equal(t, int64(200), v.UpdatedAt)
+		if assert.NotNil(t, v.UpdatedBy) {
+			assert.Equal(t, "admin", *v.UpdatedBy)
+		}
+	}
+
+	rows, err = conv.Delete(ctx, db, id.EQ(jet.Int(articleID)))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), rows)
+
+	v, err = FindOne[Article](ctx, db, jet.SELECT(all).FROM(article).WHERE(conv.Scope(id.EQ(jet.Int(articleID)))))
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+
+	// 软删除后记录仍存在
+	v, err = FindOne[Article](ctx, db, jet.SELECT(all).FROM(article).WHERE(id.EQ(jet.Int(articleID))))
+	if assert.Nil(t, err) && assert.NotNil(t, v) && assert.NotNil(t, v.DeletedAt) {
+		assert.Equal(t, int64(200), *v.DeletedAt)
+	}
+
+	// 绑定到 Repo：查询、统计自动排除已软删除的记录，删除改为软删除
+	repo := sqlkit.NewRepo[Article](db, NewDialect(article, id, all, jet.ColumnList{title, version, createdAt, updatedAt, updatedBy, deletedAt}, WithConvention(conv)))
+
+	v, err = repo.FindOne(ctx, id.EQ(jet.Int(articleID)))
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+
+	// 审计列由约定填充
+	v, err = repo.Create(ctx, &Article{Title: "repo", Version: 1})
+	if !assert.Nil(t, err) || !assert.NotNil(t, v) {
+		return
+	}
+	repoID := v.ID
+	assert.Equal(t, int64(200), v.CreatedAt)
+	assert.Equal(t, int64(200), v.UpdatedAt)
+	if assert.NotNil(t, v.UpdatedBy) {
+		assert.Equal(t, "admin", *v.UpdatedBy)
+	}
+
+	// 更新校验并递增版本，不覆盖创建时间
+	now = 250
+	rows, err = repo.Update(internal.WithOperator(ctx, "editor"), &Article{Title: "repo v2", Version: 1}, id.EQ(jet.Int(repoID)))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), rows)
+
+	v, err = repo.FindOne(ctx, id.EQ(jet.Int(repoID)))
+	if assert.Nil(t, err) && assert.NotNil(t, v) {
+		assert.Equal(t, "repo v2", v.Title)
+		assert.Equal(t, int64(2), v.Version)
+		assert.Equal(t, int64(200), v.CreatedAt)
+		assert.Equal(t, int64(250), v.UpdatedAt)
+		if assert.NotNil(t, v.UpdatedBy) {
+			assert.Equal(t, "editor", *v.UpdatedBy)
+		}
+	}
+
+	// 版本已变更
+	_, err = repo.Update(ctx, &Article{Title: "stale", Version: 1}, id.EQ(jet.Int(repoID)))
+	assert.ErrorIs(t, err, sqlkit.ErrStaleVersion)
+
+	total, err := repo.Count(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	now = 300
+	rows, err = repo.Delete(ctx, id.EQ(jet.Int(repoID)))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), rows)
+
+	list, err := repo.FindAll(ctx, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, list)
+
+	v, err = FindOne[Article](ctx, db, jet.SELECT(all).FROM(article).WHERE(id.EQ(jet.Int(repoID))))
+	if assert.Nil(t, err) && assert.NotNil(t, v) && assert.NotNil(t, v.DeletedAt) {
+		assert.Equal(t, int64(300), *v.DeletedAt)
+		assert.Equal(t, int64(300), v.UpdatedAt)
+	}
+}
@@ -2,13 +2,10 @@ package sqlite
 
 import (
 	"context"
-	"database/sql"
-	"errors"
-	"time"
 
 	"github.com/go-jet/jet/v2/qrm"
 	. "github.com/go-jet/jet/v2/sqlite"
-	"github.com/noble-gase/ne/sqlkit/internal"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
 )
 
 // M 用于 SQLite 的 INSERT & UPDATE
@@ -53,23 +50,10 @@ func (m M) Split() (cols ColumnList, vals []any) {
 //	// 执行方法
 //	sqlite.Insert(ctx, db, stmt)
 func Insert(ctx context.Context, db qrm.DB, stmt InsertStatement) (int64, error) {
-	var (
-		ret sql.Result
-		err error
-	)
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	ret, err = stmt.ExecContext(ctx, db)
+	ret, err := builder.Exec(ctx, db, stmt)
 	if err != nil {
 		return 0, err
 	}
-
 	id, _ := ret.LastInsertId()
 	return id, nil
 }
@@ -90,25 +74,7 @@ func Insert(ctx context.Context, db qrm.DB, stmt InsertStatement) (int64, error)
 //	// 执行方法
 //	sqlite.Update(ctx, db, stmt)
 func Update(ctx context.Context, db qrm.DB, stmt UpdateStatement) (int64, error) {
-	var (
-		ret sql.Result
-		err error
-	)
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	ret, err = stmt.ExecContext(ctx, db)
-	if err != nil {
-		return 0, err
-	}
-
-	rows, _ := ret.RowsAffected()
-	return rows, nil
+	return exec(ctx, db, stmt)
 }
 
 // Delete 删除记录
@@ -125,28 +91,10 @@ func Update(ctx context.Context, db qrm.DB, stmt UpdateStatement) (int64, error)
 //	// 执行方法
 //	sqlite.Delete(ctx, db, stmt)
 func Delete(ctx context.Context, db qrm.DB, stmt DeleteStatement) (int64, error) {
-	var (
-		ret sql.Result
-		err error
-	)
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	ret, err = stmt.ExecContext(ctx, db)
-	if err != nil {
-		return 0, err
-	}
-
-	rows, _ := ret.RowsAffected()
-	return rows, nil
+	return exec(ctx, db, stmt)
 }
 
-// FindOne 查询一条记录
+// FindOne 查询一条记录；不会自动排除已软删除的记录，需以 Convention.Scope 包装条件（或使用绑定 WithConvention 的 sqlkit.Repo）
 //
 // 注意：参数 T 必须为非指针类型
 //
@@ -164,30 +112,10 @@ func Delete(ctx context.Context, db qrm.DB, stmt DeleteStatement) (int64, error)
 //	// 执行方法
 //	sqlite.FindOne[model.Demo](ctx, db, stmt)
 func FindOne[T any](ctx context.Context, db qrm.DB, stmt SelectStatement) (*T, error) {
-	var (
-		dest T
-		err  error
-	)
-
-	stmt = stmt.LIMIT(1)
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
-		if errors.Is(err, qrm.ErrNoRows) {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return &dest, nil
+	return builder.FindOne[T](ctx, db, stmt.LIMIT(1))
 }
 
-// FindAll 查询多条记录
+// FindAll 查询多条记录；不会自动排除已软删除的记录，需以 Convention.Scope 包装条件（或使用绑定 WithConvention 的 sqlkit.Repo）
 //
 //	// 导入模块
 //	import (
@@ -203,25 +131,10 @@ func FindOne[T any](ctx context.Context, db qrm.DB, stmt SelectStatement) (*T, e
 //	// 执行方法
 //	sqlite.FindAll[*model.Demo](ctx, db, stmt)
 func FindAll[T any](ctx context.Context, db qrm.DB, stmt SelectStatement) ([]T, error) {
-	var (
-		dest []T
-		err  error
-	)
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	if err := stmt.QueryContext(ctx, db, &dest); err != nil {
-		return nil, err
-	}
-	return dest, nil
+	return builder.FindAll[T](ctx, db, stmt)
 }
 
-// Count 返回记录数
+// Count 返回记录数；不会自动排除已软删除的记录，需以 Convention.Scope 包装条件
 //
 //	// 导入模块
 //	import (
@@ -234,94 +147,5 @@ func FindAll[T any](ctx context.Context, db qrm.DB, stmt SelectStatement) ([]T,
 //		return count.FROM(table.Demo.Table).WHERE(table.Demo.Name.LIKE(jet.String("%hello%")))
 //	})
 func Count(ctx context.Context, db qrm.DB, fn func(count SelectStatement) SelectStatement) (int64, error) {
-	var (
-		total struct {
-			Count int64
-		}
-		err error
-	)
-
-	stmt := fn(SELECT(COUNT(STAR).AS("count")))
-
-	start := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(stmt.DebugSql()), time.Since(start), err)
-		}
-	}()
-
-	if err := stmt.QueryContext(ctx, db, &total); err != nil {
-		return 0, err
-	}
-	return total.Count, nil
-}
-
-// Paginate 分页查询
-//
-//	// 导入模块
-//	import (
-//		jet "github.com/go-jet/jet/v2/sqlite"
-//		"github.com/noble-gase/ne/sqlkit/sqlite"
-//	)
-//
-//	// 执行方法
-//	sqlite.Paginate[*model.Demo](ctx, db, func(query jet.SelectStatement) jet.SelectStatement {
-//		return query.FROM(table.Demo.Table).WHERE(table.Demo.Name.LIKE(jet.String("%hello%")))
-//	}, page, size, table.Demo.AllColumns, table.Demo.ID.DESC())
-func Paginate[T any](ctx context.Context, db qrm.DB, fn func(query SelectStatement) SelectStatement, page, size int, cols ColumnList, orderBy ...OrderByClause) ([]T, int64, error) {
-	var (
-		total struct {
-			Count int64
-		}
-		countErr error
-	)
-
-	// 构建 count 查询
-	countStmt := fn(SELECT(COUNT(STAR).AS("count")))
-
-	countStart := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(countStmt.DebugSql()), time.Since(countStart), countErr)
-		}
-	}()
-
-	countErr = countStmt.QueryContext(ctx, db, &total)
-	if countErr != nil {
-		return nil, 0, countErr
-	}
-	if total.Count == 0 {
-		return []T{}, 0, nil
-	}
-
-	// 数据查询
-
-	var (
-		dest     []T
-		queryErr error
-	)
-
-	if page <= 0 {
-		page = 1
-	}
-	if size <= 0 {
-		size = 20
-	}
-	offset := (page - 1) * size
-
-	// 构建分页查询
-	queryStmt := fn(SELECT(cols)).ORDER_BY(orderBy...).LIMIT(int64(size)).OFFSET(int64(offset))
-
-	queryStart := time.Now()
-	defer func() {
-		if internal.Logger != nil {
-			internal.Logger(ctx, internal.Minify(queryStmt.DebugSql()), time.Since(queryStart), queryErr)
-		}
-	}()
-
-	queryErr = queryStmt.QueryContext(ctx, db, &dest)
-	if queryErr != nil {
-		return nil, 0, queryErr
-	}
-	return dest, total.Count, nil
+	return builder.Count(ctx, db, fn(SELECT(COUNT(STAR).AS("count"))))
 }
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/go-jet/jet/v2/qrm"
+	. "github.com/go-jet/jet/v2/sqlite"
+	"github.com/noble-gase/ne/sqlkit/internal"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// ErrInvalidCursor 游标格式错误
+var ErrInvalidCursor = internal.ErrInvalidCursor
+
+// Order 游标分页的排序字段
+type Order = builder.Order
+
+// Asc 升序
+func Asc(col Column) Order {
+	return builder.Asc(col)
+}
+
+// Desc 降序
+func Desc(col Column) Order {
+	return builder.Desc(col)
+}
+
+// CursorPaginate 游标分页查询（Keyset Pagination），适用于大表和无限滚动；
+// 返回下一页游标，为空表示没有更多数据
+//
+// 注意：排序字段需为 NOT NULL，且排序字段组合需唯一（如：最后一个排序字段为主键）；
+// key 返回记录的排序字段值，顺序需与 orderBy 一致
+//
+//	// 导入模块
+//	import (
+//		jet "github.com/go-jet/jet/v2/sqlite"
+//		"github.com/noble-gase/ne/sqlkit/sqlite"
+//	)
+//
+//	// 执行方法（首页 cursor 传空）
+//	sqlite.CursorPaginate[*model.Demo](ctx, db, func(query jet.SelectStatement, cursor jet.BoolExpression) jet.SelectStatement {
+//		return query.FROM(table.Demo.Table).WHERE(table.Demo.Name.LIKE(jet.String("%hello%")).AND(cursor))
+//	}, cursor, size, table.Demo.AllColumns, func(v *model.Demo) []any {
+//		return []any{v.CreatedAt, v.ID}
+//	}, sqlite.Desc(table.Demo.CreatedAt), sqlite.Desc(table.Demo.ID))
+func CursorPaginate[T any](ctx context.Context, db qrm.DB, fn func(query SelectStatement, cursor BoolExpression) SelectStatement, cursor string, size int, cols ColumnList, key func(T) []any, orderBy ...Order) ([]T, string, error) {
+	return builder.CursorPaginate(ctx, db, func(cond BoolExpression, clauses []OrderByClause, limit int64) Statement {
+		return fn(SELECT(cols), cond).ORDER_BY(clauses...).LIMIT(limit)
+	}, cursor, size, key, orderBy...)
+}
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	jet "github.com/go-jet/jet/v2/sqlite"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+type Demo struct {
+	ID    int64 `sql:"primary_key"`
+	Score int64
+}
+
+var (
+	demoID     = jet.IntegerColumn("id")
+	demoScore  = jet.IntegerColumn("score")
+	demoTable  = jet.NewTable("", "demo", "", demoID, demoScore)
+	demoColumn = jet.ColumnList{demoID, demoScore}
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	if _, err = db.Exec("CREATE TABLE demo (id INTEGER PRIMARY KEY, score INTEGER NOT NULL)"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 10; i++ {
+		if _, err = db.Exec("INSERT INTO demo (id, score) VALUES (?, ?)", i, i%3); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestCursorPaginate(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	fn := func(query jet.SelectStatement, cursor jet.BoolExpression) jet.SelectStatement {
+		return query.FROM(demoTable).WHERE(demoID.GT(jet.Int(0)).AND(cursor))
+	}
+	key := func(v Demo) []any {
+		return []any{v.Score, v.ID}
+	}
+
+	// score DESC, id ASC
+	var (
+		ids    []int64
+		cursor string
+	)
+	for range 10 {
+		list, next, err := CursorPaginate(ctx, db, fn, cursor, 3, demoColumn, key, Desc(demoScore), Asc(demoID))
+		if !assert.Nil(t, err) {
+			return
+		}
+		for _, v := range list {
+			ids = append(ids, v.ID)
+		}
+		if len(next) == 0 {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, []int64{2, 5, 8, 1, 4, 7, 10, 3, 6, 9}, ids)
+
+	_, _, err := CursorPaginate(ctx, db, fn, "invalid", 3, demoColumn, key, Desc(demoScore), Asc(demoID))
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	jet "github.com/go-jet/jet/v2/sqlite"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepo(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	repo := sqlkit.NewRepo[Demo](db, NewDialect(demoTable, demoID, demoColumn, jet.ColumnList{demoScore}))
+	assert.Equal(t, "sqlite", repo.Dialect().Name())
+
+	v, err := repo.Create(ctx, &Demo{Score: 5})
+	if assert.Nil(t, err) && assert.NotNil(t, v) {
+		assert.Equal(t, Demo{ID: 11, Score: 5}, *v)
+	}
+
+	total, err := repo.Count(ctx, demoScore.EQ(jet.Int(5)))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), total)
+
+	page, err := repo.Paginate(ctx, demoScore.GT(jet.Int(0)), 2, 3, demoID.DESC())
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(8), page.Total)
+		assert.True(t, page.HasMore)
+		if assert.Len(t, page.List, 3) {
+			assert.Equal(t, int64(7), page.List[0].ID)
+		}
+	}
+
+	rows, err := repo.Update(ctx, &Demo{Score: 9}, demoID.EQ(jet.Int(11)))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), rows)
+
+	// 在事务中执行
+	err = sqlkit.Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		_, err := repo.Delete(ctx, demoID.EQ(jet.Int(11)))
+		return err
+	})
+	assert.Nil(t, err)
+
+	v, err = repo.FindOne(ctx, demoID.EQ(jet.Int(11)))
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+}
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	jet "github.com/go-jet/jet/v2/sqlite"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestFilter(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	filter := NewFilter(map[string]FilterField{
+		"id":    {Column: demoID, Ops: []Op{OpEq, OpIn, OpRange}, Sortable: true},
+		"score": {Column: demoScore, Ops: []Op{OpEq, OpGte, OpNull}},
+		"value": {Column: demoScore},
+	}, demoID.ASC())
+
+	find := func(where jet.BoolExpression, orderBy []jet.OrderByClause) []int64 {
+		list, err := FindAll[Demo](ctx, db, demoTable.SELECT(demoColumn).WHERE(where).ORDER_BY(orderBy...))
+		assert.Nil(t, err)
+		ids := make([]int64, 0, len(list))
+		for _, v := range list {
+			ids = append(ids, v.ID)
+		}
+		return ids
+	}
+
+	values, _ := url.ParseQuery("id[range]=2,8&score=1&page=1&sort=-id")
+	where, orderBy, err := filter.Parse(values)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{7, 4}, find(where, orderBy))
+
+	values, _ = url.ParseQuery("id=1&id=2,3&score_gte=2&score[null]=false")
+	where, orderBy, err = filter.Parse(values)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2}, find(where, orderBy))
+
+	// 空值忽略
+	values, _ = url.ParseQuery("id=&score[gte]=")
+	where, orderBy, err = filter.Parse(values)
+	assert.Nil(t, err)
+	assert.Len(t, find(where, orderBy), 10)
+
+	// 后缀不是操作符的未声明参数被忽略（如：score_card），已知操作符仍校验
+	values, _ = url.ParseQuery("score_card=x&id_token=abc&id=2")
+	where, orderBy, err = filter.Parse(values)
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2}, find(where, orderBy))
+
+	where, orderBy, err = filter.ParseMessage(wrapperspb.Int64(2))
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{2, 5, 8}, find(where, orderBy))
+
+	for _, query := range []string{
+		"name[eq]=hello",
+		"score[lt]=1",
+		"score_lt=1",
+		"value[in]=1,2",
+		"id=abc",
+		"id[range]=1",
+		"sort=score",
+	} {
+		values, _ = url.ParseQuery(query)
+		_, _, err = filter.Parse(values)
+		assert.ErrorIs(t, err, ErrInvalidFilter, query)
+	}
+}
@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/go-jet/jet/v2/qrm"
+	. "github.com/go-jet/jet/v2/sqlite"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// Page 分页结果
+type Page[T any] = sqlkit.Page[T]
+
+// PageOption 分页选项
+type PageOption = builder.PageOption
+
+// WithoutCount 不统计总数，仅通过多查一条记录判断是否有下一页
+func WithoutCount() PageOption {
+	return builder.WithoutCount()
+}
+
+// WithParallel 并发执行统计和数据查询（db 为 *sql.Tx 时无效）
+func WithParallel() PageOption {
+	return builder.WithParallel()
+}
+
+// WithEstimatedCount 估算总数；SQLite 不支持估算，仍执行 COUNT 统计
+func WithEstimatedCount() PageOption {
+	return builder.WithEstimatedCount()
+}
+
+// Paginate 分页查询；不会自动排除已软删除的记录，需以 Convention.Scope 包装条件（或使用绑定 WithConvention 的 sqlkit.Repo）
+//
+//	// 导入模块
+//	import (
+//		jet "github.com/go-jet/jet/v2/sqlite"
+//		"github.com/noble-gase/ne/sqlkit/sqlite"
+//	)
+//
+//	// 执行方法
+//	sqlite.Paginate[*model.Demo](ctx, db, func(query jet.SelectStatement) jet.SelectStatement {
+//		return query.FROM(table.Demo.Table).WHERE(table.Demo.Name.LIKE(jet.String("%hello%")))
+//	}, page, size, table.Demo.AllColumns, []jet.OrderByClause{table.Demo.ID.DESC()})
+//
+//	// 不统计总数
+//	sqlite.Paginate[*model.Demo](ctx, db, fn, page, size, table.Demo.AllColumns, orderBy, sqlite.WithoutCount())
+func Paginate[T any](ctx context.Context, db qrm.DB, fn func(query SelectStatement) SelectStatement, page, size int, cols ColumnList, orderBy []OrderByClause, opts ...PageOption) (*Page[T], error) {
+	return builder.Paginate[T](ctx, db, builder.Pager{
+		Select: func(limit, offset int64) Statement {
+			return fn(SELECT(cols)).ORDER_BY(orderBy...).LIMIT(limit).OFFSET(offset)
+		},
+		Count: fn(SELECT(COUNT(STAR).AS("count"))),
+	}, page, size, opts...)
+}
@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	jet "github.com/go-jet/jet/v2/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	fn := func(query jet.SelectStatement) jet.SelectStatement {
+		return query.FROM(demoTable).WHERE(demoScore.GT(jet.Int(0)))
+	}
+	orderBy := []jet.OrderByClause{demoID.ASC()}
+
+	page, err := Paginate[Demo](ctx, db, fn, 1, 3, demoColumn, orderBy)
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(7), page.Total)
+		assert.True(t, page.HasMore)
+		assert.Equal(t, 3, len(page.List))
+	}
+
+	page, err = Paginate[Demo](ctx, db, fn, 3, 3, demoColumn, orderBy, WithParallel())
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(7), page.Total)
+		assert.False(t, page.HasMore)
+		if assert.Equal(t, 1, len(page.List)) {
+			assert.Equal(t, int64(10), page.List[0].ID)
+		}
+	}
+
+	page, err = Paginate[Demo](ctx, db, fn, 2, 3, demoColumn, orderBy, WithoutCount())
+	if assert.Nil(t, err) {
+		assert.Equal(t, int64(-1), page.Total)
+		assert.True(t, page.HasMore)
+		assert.Equal(t, 3, len(page.List))
+	}
+}
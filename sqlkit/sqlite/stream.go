@@ -0,0 +1,49 @@
+package sqlite
+
+import (
+	"context"
+	"iter"
+
+	"github.com/go-jet/jet/v2/qrm"
+	. "github.com/go-jet/jet/v2/sqlite"
+	"github.com/noble-gase/ne/sqlkit/internal/builder"
+)
+
+// Stream 流式查询，逐行扫描记录，适用于导出等大结果集场景；迭代提前结束时自动关闭 rows
+//
+// 注意：参数 T 必须为非指针类型；迭代期间会占用一个数据库连接
+//
+//	// 导入模块
+//	import (
+//		jet "github.com/go-jet/jet/v2/sqlite"
+//		"github.com/noble-gase/ne/sqlkit/sqlite"
+//	)
+//
+//	// 语句示例
+//	table.Demo.SELECT(table.Demo.AllColumns).WHERE(table.Demo.Name.LIKE(jet.String("%hello%")))
+//
+//	// 执行方法
+//	for v, err := range sqlite.Stream[model.Demo](ctx, db, stmt) {
+//		if err != nil {
+//			return err
+//		}
+//		// todo: do something
+//	}
+func Stream[T any](ctx context.Context, db qrm.DB, stmt SelectStatement) iter.Seq2[T, error] {
+	return builder.Stream[T](ctx, db, stmt)
+}
+
+// StreamChunk 流式查询，按 size 条记录分批返回，适用于批量处理（如：批量写入其它存储）
+//
+// 注意：参数 T 必须为非指针类型
+//
+//	// 执行方法
+//	for list, err := range sqlite.StreamChunk[model.Demo](ctx, db, stmt, 1000) {
+//		if err != nil {
+//			return err
+//		}
+//		// todo: do something
+//	}
+func StreamChunk[T any](ctx context.Context, db qrm.DB, stmt SelectStatement, size int) iter.Seq2[[]T, error] {
+	return builder.StreamChunk[T](ctx, db, stmt, size)
+}
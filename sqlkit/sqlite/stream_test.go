@@ -0,0 +1,39 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	jet "github.com/go-jet/jet/v2/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	stmt := jet.SELECT(demoColumn).FROM(demoTable).ORDER_BY(demoID.ASC())
+
+	var ids []int64
+	for v, err := range Stream[Demo](ctx, db, stmt) {
+		if !assert.Nil(t, err) {
+			return
+		}
+		ids = append(ids, v.ID)
+		if v.ID == 5 {
+			break
+		}
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+	// 提前结束后连接已释放
+	assert.Equal(t, 0, db.Stats().InUse)
+
+	var sizes []int
+	for list, err := range StreamChunk[Demo](ctx, db, stmt, 4) {
+		if !assert.Nil(t, err) {
+			return
+		}
+		sizes = append(sizes, len(list))
+	}
+	assert.Equal(t, []int{4, 4, 2}, sizes)
+}
@@ -0,0 +1,117 @@
+package sqltest
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/internal"
+)
+
+// Recorder 记录通过 sqlkit.SetLogger 输出的 SQL
+type Recorder struct {
+	tb      testing.TB
+	mutex   sync.Mutex
+	queries []sqlkit.Query
+}
+
+// Capture 开始记录执行的 SQL，测试结束后恢复原来的日志函数；
+// 日志函数为全局设置，使用 Capture 的测试不应并行执行
+//
+//	rec := sqltest.Capture(t)
+//
+//	repo.FindOne(ctx, ...)
+//
+//	rec.AssertCount(1)
+//	rec.AssertContains("SELECT", "WHERE demo.id = ?")
+func Capture(tb testing.TB) *Recorder {
+	tb.Helper()
+
+	r := &Recorder{tb: tb}
+
+	prev := internal.Logger
+	sqlkit.SetLogger(func(ctx context.Context, q sqlkit.Query) {
+		r.mutex.Lock()
+		r.queries = append(r.queries, q)
+		r.mutex.Unlock()
+	})
+	tb.Cleanup(func() {
+		sqlkit.SetLogger(prev)
+	})
+	return r
+}
+
+// Queries 返回已记录的 SQL
+func (r *Recorder) Queries() []sqlkit.Query {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return slices.Clone(r.queries)
+}
+
+// Reset 清空已记录的 SQL
+func (r *Recorder) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.queries = nil
+}
+
+// AssertCount 断言记录的 SQL 数量
+func (r *Recorder) AssertCount(n int) bool {
+	r.tb.Helper()
+
+	queries := r.Queries()
+	if len(queries) != n {
+		r.tb.Errorf("sqltest: expected %d queries, got %d:\n%s", n, len(queries), dump(queries))
+		return false
+	}
+	return true
+}
+
+// AssertContains 断言记录的 SQL 按顺序包含 fragments（每个片段匹配一条 SQL，不要求连续）
+func (r *Recorder) AssertContains(fragments ...string) bool {
+	r.tb.Helper()
+
+	queries := r.Queries()
+
+	i := 0
+	for _, q := range queries {
+		if i < len(fragments) && strings.Contains(q.SQL, fragments[i]) {
+			i++
+		}
+	}
+	if i < len(fragments) {
+		r.tb.Errorf("sqltest: no query contains %q (in order), got:\n%s", fragments[i], dump(queries))
+		return false
+	}
+	return true
+}
+
+// AssertFingerprints 断言记录的 SQL 指纹（见 sqlkit.Query）与 expected 完全一致
+func (r *Recorder) AssertFingerprints(expected ...string) bool {
+	r.tb.Helper()
+
+	queries := r.Queries()
+
+	actual := make([]string, 0, len(queries))
+	for _, q := range queries {
+		actual = append(actual, q.Fingerprint)
+	}
+	if !slices.Equal(actual, expected) {
+		r.tb.Errorf("sqltest: fingerprints mismatch\nexpected:\n  %s\nactual:\n  %s", strings.Join(expected, "\n  "), strings.Join(actual, "\n  "))
+		return false
+	}
+	return true
+}
+
+func dump(queries []sqlkit.Query) string {
+	var b strings.Builder
+	for _, q := range queries {
+		b.WriteString("  ")
+		b.WriteString(q.SQL)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,139 @@
+package sqltest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/noble-gase/ne/sqlkit"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFixtures 从 fsys 加载 fixture 并插入数据库，文件名（不含扩展名）为表名，内容为记录列表：
+//
+//	# demo.yml
+//	- id: 1
+//	  name: hello
+//	- id: 2
+//	  name: world
+//
+// patterns 为 fs.Glob 匹配模式，按顺序加载（用于处理外键依赖），默认：*.yml、*.yaml、*.json；
+// 使用 ctx 中的事务（见 Tx），嵌套的 map 和数组序列化为 JSON 插入
+func LoadFixtures(tb testing.TB, ctx context.Context, db *sql.DB, fsys fs.FS, patterns ...string) {
+	tb.Helper()
+
+	if len(patterns) == 0 {
+		patterns = []string{"*.yml", "*.yaml", "*.json"}
+	}
+
+	var files []string
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			tb.Fatalf("sqltest: glob %s: %v", pattern, err)
+		}
+		for _, v := range matches {
+			if !slices.Contains(files, v) {
+				files = append(files, v)
+			}
+		}
+	}
+
+	d := dialectOf(db)
+	exec := sqlkit.Executor(ctx, db)
+	for _, file := range files {
+		rows, err := readFixture(fsys, file)
+		if err != nil {
+			tb.Fatalf("sqltest: read fixture %s: %v", file, err)
+		}
+
+		table := strings.TrimSuffix(path.Base(file), path.Ext(file))
+		for i, row := range rows {
+			query, args := insertSQL(d, table, row)
+			if _, err = exec.ExecContext(ctx, query, args...); err != nil {
+				tb.Fatalf("sqltest: load fixture %s[%d]: %v", file, i, err)
+			}
+		}
+	}
+}
+
+// Truncate 清空表数据（PgSQL 使用 TRUNCATE ... RESTART IDENTITY CASCADE，其它使用 DELETE FROM）；使用 ctx 中的事务
+func Truncate(tb testing.TB, ctx context.Context, db *sql.DB, tables ...string) {
+	tb.Helper()
+
+	d := dialectOf(db)
+	exec := sqlkit.Executor(ctx, db)
+	for _, table := range tables {
+		query := "DELETE FROM " + d.quote(table)
+		if d == dialectPgSQL {
+			query = "TRUNCATE TABLE " + d.quote(table) + " RESTART IDENTITY CASCADE"
+		}
+		if _, err := exec.ExecContext(ctx, query); err != nil {
+			tb.Fatalf("sqltest: truncate %s: %v", table, err)
+		}
+	}
+}
+
+func readFixture(fsys fs.FS, file string) ([]map[string]any, error) {
+	b, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]any
+	switch strings.ToLower(path.Ext(file)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		err = decoder.Decode(&rows)
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(b, &rows)
+	default:
+		err = fmt.Errorf("unsupported fixture format: %s", path.Ext(file))
+	}
+	return rows, err
+}
+
+func insertSQL(d dialect, table string, row map[string]any) (string, []any) {
+	cols := make([]string, 0, len(row))
+	for k := range row {
+		cols = append(cols, k)
+	}
+	slices.Sort(cols)
+
+	var (
+		names        = make([]string, 0, len(cols))
+		placeholders = make([]string, 0, len(cols))
+		args         = make([]any, 0, len(cols))
+	)
+	for i, c := range cols {
+		names = append(names, d.quote(c))
+		placeholders = append(placeholders, d.placeholder(i+1))
+		args = append(args, fixtureValue(row[c]))
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", d.quote(table), strings.Join(names, ", "), strings.Join(placeholders, ", ")), args
+}
+
+func fixtureValue(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			return n
+		}
+		if f, err := x.Float64(); err == nil {
+			return f
+		}
+		return x.String()
+	case map[string]any, []any:
+		b, _ := json.Marshal(x)
+		return string(b)
+	}
+	return v
+}
@@ -0,0 +1,111 @@
+// Package sqltest 数据仓库测试工具：内存 SQLite、fixture 加载、事务回滚隔离、SQL 断言
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/mattn/go-sqlite3"
+	"github.com/noble-gase/ne/sqlkit"
+)
+
+var seq atomic.Int64
+
+// SQLite 返回当前测试独占的内存数据库（测试结束后自动销毁），并依次执行 ddl
+//
+//	db := sqltest.SQLite(t, `CREATE TABLE demo (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)`)
+func SQLite(tb testing.TB, ddl ...string) *sql.DB {
+	tb.Helper()
+
+	name := strings.NewReplacer("/", "_", " ", "_", "#", "_").Replace(tb.Name())
+	dsn := fmt.Sprintf("file:sqltest_%s_%d?mode=memory&cache=shared&_fk=1", name, seq.Add(1))
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		tb.Fatalf("sqltest: open sqlite: %v", err)
+	}
+
+	// 内存数据库在最后一个连接关闭时销毁，保持一个连接直到测试结束
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		_ = db.Close()
+		tb.Fatalf("sqltest: open sqlite: %v", err)
+	}
+	tb.Cleanup(func() {
+		_ = conn.Close()
+		_ = db.Close()
+	})
+
+	for _, v := range ddl {
+		if _, err = db.Exec(v); err != nil {
+			tb.Fatalf("sqltest: exec ddl: %v\n%s", err, v)
+		}
+	}
+	return db
+}
+
+// Tx 开启事务并绑定到返回的 ctx，测试结束后回滚，用于 MySQL、PgSQL 等共享数据库的测试隔离；
+// 被测代码需通过 sqlkit.Executor 或 sqlkit.Transaction 使用 ctx 中的事务
+//
+//	ctx, _ := sqltest.Tx(t, db)
+//	sqltest.LoadFixtures(t, ctx, db, os.DirFS("testdata/fixtures"))
+//
+//	repo.Create(ctx, ...)
+func Tx(tb testing.TB, db *sql.DB) (context.Context, *sql.Tx) {
+	tb.Helper()
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	if err != nil {
+		tb.Fatalf("sqltest: begin transaction: %v", err)
+	}
+	tb.Cleanup(func() {
+		_ = tx.Rollback()
+	})
+	return sqlkit.WithTx(context.Background(), db, tx), tx
+}
+
+type dialect int
+
+const (
+	dialectSQLite dialect = iota
+	dialectMySQL
+	dialectPgSQL
+)
+
+func dialectOf(db *sql.DB) dialect {
+	switch db.Driver().(type) {
+	case *mysql.MySQLDriver:
+		return dialectMySQL
+	case *stdlib.Driver:
+		return dialectPgSQL
+	case *sqlite3.SQLiteDriver:
+		return dialectSQLite
+	}
+	return dialectSQLite
+}
+
+// quote 引用标识符，支持 schema.table 形式
+func (d dialect) quote(name string) string {
+	parts := strings.Split(name, ".")
+	for i, v := range parts {
+		if d == dialectMySQL {
+			parts[i] = "`" + strings.ReplaceAll(v, "`", "``") + "`"
+		} else {
+			parts[i] = `"` + strings.ReplaceAll(v, `"`, `""`) + `"`
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func (d dialect) placeholder(i int) string {
+	if d == dialectPgSQL {
+		return fmt.Sprintf("$%d", i)
+	}
+	return "?"
+}
@@ -0,0 +1,100 @@
+package sqltest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"testing/fstest"
+
+	jet "github.com/go-jet/jet/v2/sqlite"
+	"github.com/noble-gase/ne/sqlkit"
+	"github.com/noble-gase/ne/sqlkit/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+const ddl = `CREATE TABLE demo (id INTEGER PRIMARY KEY, name TEXT NOT NULL, extra TEXT)`
+
+var fixtures = fstest.MapFS{
+	"demo.yml": {Data: []byte(`
+- id: 1
+  name: hello
+- id: 2
+  name: world
+  extra:
+    tags: [a, b]
+`)},
+	"more/demo.json": {Data: []byte(`[{"id": 3, "name": "json"}]`)},
+}
+
+func count(t *testing.T, ctx context.Context, db *sql.DB) int {
+	rows, err := sqlkit.Executor(ctx, db).QueryContext(ctx, "SELECT COUNT(*) FROM demo")
+	if !assert.Nil(t, err) {
+		return -1
+	}
+	defer rows.Close()
+
+	var n int
+	if rows.Next() {
+		assert.Nil(t, rows.Scan(&n))
+	}
+	return n
+}
+
+func TestSQLite(t *testing.T) {
+	ctx := context.Background()
+
+	db := SQLite(t, ddl)
+	LoadFixtures(t, ctx, db, fixtures, "demo.yml")
+	assert.Equal(t, 2, count(t, ctx, db))
+
+	var extra string
+	assert.Nil(t, db.QueryRow("SELECT extra FROM demo WHERE id = 2").Scan(&extra))
+	assert.JSONEq(t, `{"tags": ["a", "b"]}`, extra)
+
+	// 每个测试独占数据库
+	other := SQLite(t, ddl)
+	assert.Equal(t, 0, count(t, ctx, other))
+
+	Truncate(t, ctx, db, "demo")
+	assert.Equal(t, 0, count(t, ctx, db))
+}
+
+func TestTx(t *testing.T) {
+	db := SQLite(t, ddl)
+
+	t.Run("rollback", func(t *testing.T) {
+		ctx, _ := Tx(t, db)
+		LoadFixtures(t, ctx, db, fixtures, "*.yml", "more/*.json")
+		assert.Equal(t, 3, count(t, ctx, db))
+	})
+	assert.Equal(t, 0, count(t, context.Background(), db))
+}
+
+func TestCapture(t *testing.T) {
+	ctx := context.Background()
+
+	db := SQLite(t, ddl)
+	LoadFixtures(t, ctx, db, fixtures)
+
+	var (
+		id   = jet.IntegerColumn("id")
+		name = jet.StringColumn("name")
+		demo = jet.NewTable("", "demo", "", id, name)
+	)
+
+	rec := Capture(t)
+	_, err := sqlite.FindAll[struct{ Name string }](ctx, db, demo.SELECT(name.AS("name")).WHERE(id.GT(jet.Int(1))))
+	assert.Nil(t, err)
+	_, err = sqlite.Update(ctx, db, demo.UPDATE(name).SET(jet.String("bye")).WHERE(id.EQ(jet.Int(1))))
+	assert.Nil(t, err)
+
+	rec.AssertCount(2)
+	rec.AssertContains("SELECT", "UPDATE demo")
+	rec.AssertFingerprints(
+		"SELECT demo.name AS \"name\" FROM demo WHERE demo.id > ?;",
+		"UPDATE demo SET name = ? WHERE demo.id = ?;",
+	)
+
+	rec.Reset()
+	assert.Empty(t, rec.Queries())
+}
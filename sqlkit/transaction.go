@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 	"runtime/debug"
+	"strconv"
+
+	"github.com/go-jet/jet/v2/qrm"
 )
 
 type (
@@ -12,77 +15,108 @@ type (
 	TX = map[string]*sql.Tx
 )
 
-// Transaction 执行数据库事务
-func Transaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sql.Tx) error, opts ...*sql.TxOptions) (err error) {
-	var opt *sql.TxOptions
-	if len(opts) != 0 {
-		opt = opts[0]
-	}
+type txKey struct {
+	db *sql.DB
+}
 
-	tx, _err := db.BeginTx(ctx, opt)
-	if _err != nil {
-		err = fmt.Errorf("begin transaction: %w", _err)
-		return
+// txState 保存在 ctx 中的事务
+type txState struct {
+	tx  *sql.Tx
+	seq int
+}
+
+// txHandle 事务句柄：顶层事务 或 嵌套事务的保存点
+type txHandle struct {
+	state     *txState
+	savepoint string
+}
+
+func (h *txHandle) commit(ctx context.Context) error {
+	if len(h.savepoint) != 0 {
+		_, err := h.state.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+h.savepoint)
+		return err
 	}
+	return h.state.tx.Commit()
+}
 
-	rollback := func(err error) error {
-		if e := tx.Rollback(); e != nil {
-			err = fmt.Errorf("%w; rollback: %w", err, e)
-		}
+func (h *txHandle) rollback(ctx context.Context) error {
+	if len(h.savepoint) != 0 {
+		_, err := h.state.tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+h.savepoint)
 		return err
 	}
+	return h.state.tx.Rollback()
+}
 
-	defer func() {
-		if r := recover(); r != nil {
-			// if panic, should rollback
-			e := fmt.Errorf("transaction panic recovered: %+v", r)
-			err = fmt.Errorf("%w\n%s", rollback(e), string(debug.Stack()))
+// begin 开启事务；若 ctx 中已存在 db 的事务，则创建保存点加入该事务
+func begin(ctx context.Context, db *sql.DB, opt *sql.TxOptions) (context.Context, *txHandle, error) {
+	if state, ok := ctx.Value(txKey{db: db}).(*txState); ok {
+		state.seq++
+		sp := "sp_" + strconv.Itoa(state.seq)
+		if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+sp); err != nil {
+			return ctx, nil, fmt.Errorf("savepoint: %w", err)
 		}
-	}()
+		return ctx, &txHandle{state: state, savepoint: sp}, nil
+	}
 
-	if e := fn(ctx, tx); e != nil {
-		err = rollback(e)
-		return
+	tx, err := db.BeginTx(ctx, opt)
+	if err != nil {
+		return ctx, nil, err
 	}
+	state := &txState{tx: tx}
+	return context.WithValue(ctx, txKey{db: db}, state), &txHandle{state: state}, nil
+}
 
-	if e := tx.Commit(); e != nil {
-		err = rollback(fmt.Errorf("commit: %w", e))
+// Executor 返回 ctx 中 db 对应的事务，不存在时返回 db 本身；
+// 用于 curd 方法，使其自动加入外层事务
+//
+//	mysql.FindOne[model.Demo](ctx, sqlkit.Executor(ctx, db), stmt)
+func Executor(ctx context.Context, db *sql.DB) qrm.DB {
+	if state, ok := ctx.Value(txKey{db: db}).(*txState); ok {
+		return state.tx
 	}
-	return
+	return db
+}
+
+// WithTx 将已开启的事务 tx 绑定到 ctx，之后 Executor 返回 tx，Transaction 通过 SAVEPOINT 加入 tx；
+// tx 的提交和回滚由调用方负责（如：测试结束后回滚，见 sqltest.Tx），其中注册的 AfterCommit/AfterRollback 回调不会执行
+func WithTx(ctx context.Context, db *sql.DB, tx *sql.Tx) context.Context {
+	ctx = context.WithValue(ctx, hooksKey{}, &txHooks{})
+	return context.WithValue(ctx, txKey{db: db}, &txState{tx: tx})
 }
 
-// TransactionX 执行多数据库事务
-func TransactionX(ctx context.Context, db DB, fn func(ctx context.Context, tx TX) error, opts ...*sql.TxOptions) (err error) {
-	var opt *sql.TxOptions
-	if len(opts) != 0 {
-		opt = opts[0]
+// Transaction 执行数据库事务
+//
+// 若 ctx 中已存在 db 的事务（即嵌套调用），则通过 SAVEPOINT 加入该事务：
+// fn 返回错误时仅回滚到保存点，外层事务不受影响（此时 opts 被忽略）
+//
+//	sqlkit.Transaction(ctx, db, fn, sqlkit.WithTxOptions(&sql.TxOptions{Isolation: sql.LevelSerializable}), sqlkit.WithRetry(3, 50*time.Millisecond))
+func Transaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sql.Tx) error, opts ...TxOption) error {
+	o := newTxOptions(opts)
+	if _, ok := ctx.Value(txKey{db: db}).(*txState); ok {
+		return transaction(ctx, db, fn, o.tx)
 	}
+	return o.run(ctx, func() error {
+		return transaction(ctx, db, fn, o.tx)
+	})
+}
 
-	tx := make(TX, len(db))
-	for k, v := range db {
-		if v == nil {
-			err = fmt.Errorf("db(%s) is nil (forgotten initialize?)", k)
-			return
-		}
+func transaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context, tx *sql.Tx) error, opt *sql.TxOptions) (err error) {
+	outer := ctx
 
-		x, e := v.BeginTx(ctx, opt)
-		if e != nil {
-			// 回滚已开启的事务
-			for _, t := range tx {
-				_ = t.Rollback()
-			}
-			err = fmt.Errorf("begin transaction (%s): %w", k, e)
-			return
-		}
-		tx[k] = x
+	ctx, h, _err := begin(ctx, db, opt)
+	if _err != nil {
+		err = fmt.Errorf("begin transaction: %w", _err)
+		return
 	}
 
+	hooks := newTxHooks(outer)
+	ctx = context.WithValue(ctx, hooksKey{}, hooks)
+
 	rollback := func(err error) error {
-		for k, v := range tx {
-			if e := v.Rollback(); e != nil {
-				err = fmt.Errorf("%w; rollback(%s): %w", err, k, e)
-			}
+		if e := h.rollback(ctx); e != nil {
+			err = fmt.Errorf("%w; rollback: %w", err, e)
 		}
+		hooks.rolledBack(outer)
 		return err
 	}
 
@@ -94,16 +128,15 @@ func TransactionX(ctx context.Context, db DB, fn func(ctx context.Context, tx TX
 		}
 	}()
 
-	if e := fn(ctx, tx); e != nil {
+	if e := fn(ctx, h.state.tx); e != nil {
 		err = rollback(e)
 		return
 	}
 
-	for k, v := range tx {
-		if e := v.Commit(); e != nil {
-			err = rollback(fmt.Errorf("commit(%s): %w", k, e))
-			return
-		}
+	if e := h.commit(ctx); e != nil {
+		err = rollback(fmt.Errorf("commit: %w", e))
+		return
 	}
+	hooks.committed(outer, len(h.savepoint) != 0)
 	return
 }
@@ -0,0 +1,147 @@
+package sqlkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := NewDB(&Config{
+		Driver: "sqlite3",
+		DSN:    "file:" + filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	if _, err = db.Exec("CREATE TABLE demo (name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func countDemo(t *testing.T, db *sql.DB) int {
+	var n int
+	assert.Nil(t, db.QueryRow("SELECT COUNT(*) FROM demo").Scan(&n))
+	return n
+}
+
+func TestNestedTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	err := Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := Executor(ctx, db).ExecContext(ctx, "INSERT INTO demo (name) VALUES ('outer')"); err != nil {
+			return err
+		}
+		// 内层事务失败，仅回滚到保存点
+		err := Transaction(ctx, db, func(ctx context.Context, inner *sql.Tx) error {
+			assert.Same(t, tx, inner)
+			if _, err := Executor(ctx, db).ExecContext(ctx, "INSERT INTO demo (name) VALUES ('inner')"); err != nil {
+				return err
+			}
+			return errors.New("oh no")
+		})
+		assert.NotNil(t, err)
+		// 内层事务成功
+		return Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO demo (name) VALUES ('inner')")
+			return err
+		})
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, countDemo(t, db))
+	assert.Equal(t, db, Executor(ctx, db))
+}
+
+func TestTransactionRetry(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	errRetry := errors.New("retry")
+
+	attempts := 0
+	err := Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		attempts++
+		if _, err := tx.ExecContext(ctx, "INSERT INTO demo (name) VALUES ('hello')"); err != nil {
+			return err
+		}
+		if attempts < 3 {
+			return errRetry
+		}
+		return nil
+	}, WithRetry(3, time.Millisecond, func(err error) bool {
+		return errors.Is(err, errRetry)
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 1, countDemo(t, db))
+}
+
+func TestTransactionHooks(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t)
+
+	var events []string
+	err := Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		AfterCommit(ctx, func(ctx context.Context) {
+			// 回调中不再处于事务中
+			assert.Equal(t, db, Executor(ctx, db))
+			events = append(events, "outer:commit")
+		})
+		_ = Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+			AfterCommit(ctx, func(ctx context.Context) {
+				events = append(events, "inner-1:commit")
+			})
+			return nil
+		})
+		_ = Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+			AfterCommit(ctx, func(ctx context.Context) {
+				events = append(events, "inner-2:commit")
+			})
+			AfterRollback(ctx, func(ctx context.Context) {
+				events = append(events, "inner-2:rollback")
+			})
+			return errors.New("oh no")
+		})
+		// 内层事务的提交回调应在外层事务提交后执行
+		assert.Equal(t, []string{"inner-2:rollback"}, events)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"inner-2:rollback", "outer:commit", "inner-1:commit"}, events)
+}
+
+func TestTransactionHooksWithTx(t *testing.T) {
+	db := newTestDB(t)
+
+	tx, err := db.Begin()
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer tx.Rollback()
+
+	var events []string
+	ctx := WithTx(context.Background(), db, tx)
+	err = Transaction(ctx, db, func(ctx context.Context, tx *sql.Tx) error {
+		AfterCommit(ctx, func(ctx context.Context) {
+			events = append(events, "commit")
+		})
+		return nil
+	})
+	assert.Nil(t, err)
+	// 保存点释放后外层事务仍可能回滚，不执行提交回调
+	assert.Empty(t, events)
+
+	AfterCommit(ctx, func(ctx context.Context) {
+		events = append(events, "commit")
+	})
+	assert.Empty(t, events)
+}
@@ -0,0 +1,332 @@
+package sqlkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// TxXError 多数据库事务提交阶段失败的报告，可通过 errors.As 获取
+type TxXError struct {
+	// Err 导致失败的错误
+	Err error
+	// Committed 已提交的库（按提交顺序）
+	Committed []string
+	// Released 通过 SAVEPOINT 加入外层事务且已释放保存点的库，其结果由外层事务决定（不执行补偿）
+	Released []string
+	// RolledBack 已回滚的库
+	RolledBack []string
+	// Failed 提交失败的库，其状态未知（如：网络错误时可能已提交）
+	Failed string
+	// InDoubt 两阶段提交中已 PREPARE 但 COMMIT PREPARED 失败的库（db → gid），
+	// 可人工执行 COMMIT PREPARED '<gid>' 修复
+	InDoubt map[string]string
+	// Compensated 已提交库的补偿结果（nil 表示补偿成功）
+	Compensated map[string]error
+}
+
+func (e *TxXError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+	if len(e.Committed) != 0 {
+		b.WriteString("; committed: [" + strings.Join(e.Committed, ", ") + "]")
+	}
+	if len(e.Released) != 0 {
+		b.WriteString("; released: [" + strings.Join(e.Released, ", ") + "]")
+	}
+	if len(e.RolledBack) != 0 {
+		b.WriteString("; rolled back: [" + strings.Join(e.RolledBack, ", ") + "]")
+	}
+	if len(e.InDoubt) != 0 {
+		keys := make([]string, 0, len(e.InDoubt))
+		for k, v := range e.InDoubt {
+			keys = append(keys, k+"("+v+")")
+		}
+		sort.Strings(keys)
+		b.WriteString("; in doubt: [" + strings.Join(keys, ", ") + "]")
+	}
+	for _, k := range e.Committed {
+		if err, ok := e.Compensated[k]; ok && err != nil {
+			b.WriteString("; compensate(" + k + "): " + err.Error())
+		}
+	}
+	return b.String()
+}
+
+func (e *TxXError) Unwrap() error {
+	return e.Err
+}
+
+// partial 是否存在已提交或状态未知的库
+func (e *TxXError) partial() bool {
+	return len(e.Committed) != 0 || len(e.InDoubt) != 0 || len(e.Failed) != 0
+}
+
+// TransactionX 执行多数据库事务
+//
+// 按 WithCommitOrder 指定的顺序（默认按名称排序）依次提交；
+// 提交阶段失败时返回 *TxXError，报告各库的提交/回滚情况，并对已提交的库执行 WithCompensate 设置的补偿。
+// 同 Transaction，ctx 中已存在事务的 db 将通过 SAVEPOINT 加入该事务
+func TransactionX(ctx context.Context, db DB, fn func(ctx context.Context, tx TX) error, opts ...TxOption) error {
+	o := newTxOptions(opts)
+
+	// 部分提交后不可重试
+	retryable := o.retryable
+	o.retryable = func(err error) bool {
+		var x *TxXError
+		if errors.As(err, &x) && x.partial() {
+			return false
+		}
+		return retryable(err)
+	}
+
+	for _, v := range db {
+		if _, ok := ctx.Value(txKey{db: v}).(*txState); ok {
+			if o.twoPhase {
+				return errors.New("two-phase commit cannot join an outer transaction")
+			}
+			return transactionX(ctx, db, fn, o)
+		}
+	}
+	return o.run(ctx, func() error {
+		return transactionX(ctx, db, fn, o)
+	})
+}
+
+func transactionX(ctx context.Context, db DB, fn func(ctx context.Context, tx TX) error, o *txOptions) (err error) {
+	outer := ctx
+
+	keys := commitOrder(db, o.order)
+	for _, k := range keys {
+		if db[k] == nil {
+			err = fmt.Errorf("db(%s) is nil (forgotten initialize?)", k)
+			return
+		}
+		if o.twoPhase {
+			if _, ok := db[k].Driver().(*stdlib.Driver); !ok {
+				err = fmt.Errorf("db(%s): two-phase commit is only supported by PostgreSQL", k)
+				return
+			}
+		}
+	}
+
+	tx := make(TX, len(db))
+	hs := make(map[string]*txHandle, len(db))
+	for i, k := range keys {
+		c, h, e := begin(ctx, db[k], o.tx)
+		if e != nil {
+			// 回滚已开启的事务
+			for _, v := range slices.Backward(keys[:i]) {
+				_ = hs[v].rollback(ctx)
+			}
+			err = fmt.Errorf("begin transaction (%s): %w", k, e)
+			return
+		}
+		ctx = c
+		tx[k] = h.state.tx
+		hs[k] = h
+	}
+
+	hooks := newTxHooks(outer)
+	ctx = context.WithValue(ctx, hooksKey{}, hooks)
+
+	// rollback 按逆序回滚，返回已回滚的库
+	rollback := func(keys []string) ([]string, error) {
+		var errs []error
+		rolledBack := make([]string, 0, len(keys))
+		for _, k := range slices.Backward(keys) {
+			if e := hs[k].rollback(ctx); e != nil {
+				errs = append(errs, fmt.Errorf("rollback(%s): %w", k, e))
+				continue
+			}
+			rolledBack = append(rolledBack, k)
+		}
+		return rolledBack, errors.Join(errs...)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			// if panic, should rollback
+			err = fmt.Errorf("transaction panic recovered: %+v", r)
+			if _, e := rollback(keys); e != nil {
+				err = fmt.Errorf("%w; %w", err, e)
+			}
+			hooks.rolledBack(outer)
+			err = fmt.Errorf("%w\n%s", err, string(debug.Stack()))
+		}
+	}()
+
+	if e := fn(ctx, tx); e != nil {
+		err = e
+		if _, e = rollback(keys); e != nil {
+			err = fmt.Errorf("%w; %w", err, e)
+		}
+		hooks.rolledBack(outer)
+		return
+	}
+
+	if o.twoPhase {
+		return twoPhaseCommit(ctx, outer, db, keys, hs, hooks)
+	}
+
+	// 存在加入外层事务的库时，回调转交外层事务，待其提交/回滚后执行
+	nested := false
+	for _, k := range keys {
+		if len(hs[k].savepoint) != 0 {
+			nested = true
+		}
+	}
+
+	var committed, released []string
+	for i, k := range keys {
+		if e := hs[k].commit(ctx); e != nil {
+			report := &TxXError{
+				Err:       fmt.Errorf("commit(%s): %w", k, e),
+				Committed: committed,
+				Released:  released,
+				Failed:    k,
+			}
+			if len(hs[k].savepoint) != 0 {
+				// 保存点释放失败，回滚到保存点
+				if hs[k].rollback(ctx) == nil {
+					report.Failed = ""
+					report.RolledBack = append(report.RolledBack, k)
+				}
+			}
+			rolledBack, e := rollback(keys[i+1:])
+			report.RolledBack = append(report.RolledBack, rolledBack...)
+			if e != nil {
+				report.Err = fmt.Errorf("%w; %w", report.Err, e)
+			}
+			report.compensate(outer, o.compensate)
+			hooks.rolledBack(outer)
+			return report
+		}
+		if len(hs[k].savepoint) != 0 {
+			released = append(released, k)
+		} else {
+			committed = append(committed, k)
+		}
+	}
+	hooks.committed(outer, nested)
+	return
+}
+
+// twoPhaseCommit 两阶段提交：全部 PREPARE TRANSACTION 成功后再 COMMIT PREPARED
+func twoPhaseCommit(ctx, outer context.Context, db DB, keys []string, hs map[string]*txHandle, hooks *txHooks) error {
+	xid := uuid.New().String()
+	gids := make(map[string]string, len(keys))
+
+	// 阶段一：PREPARE
+	prepared := make([]string, 0, len(keys))
+	for i, k := range keys {
+		gid := xid + ":" + k
+		_, e := hs[k].state.tx.ExecContext(ctx, "PREPARE TRANSACTION "+quote(gid))
+		// PREPARE 后事务已与连接分离，释放 *sql.Tx
+		_ = hs[k].state.tx.Rollback()
+		if e != nil {
+			report := &TxXError{
+				Err:        fmt.Errorf("prepare(%s): %w", k, e),
+				RolledBack: []string{k},
+			}
+			for _, v := range slices.Backward(keys[i+1:]) {
+				if hs[v].rollback(ctx) == nil {
+					report.RolledBack = append(report.RolledBack, v)
+				}
+			}
+			for _, v := range slices.Backward(prepared) {
+				if _, err := db[v].ExecContext(context.WithoutCancel(ctx), "ROLLBACK PREPARED "+quote(gids[v])); err != nil {
+					report.Err = fmt.Errorf("%w; rollback prepared(%s): %w", report.Err, v, err)
+					continue
+				}
+				report.RolledBack = append(report.RolledBack, v)
+			}
+			hooks.rolledBack(outer)
+			return report
+		}
+		gids[k] = gid
+		prepared = append(prepared, k)
+	}
+
+	// 阶段二：COMMIT PREPARED
+	report := &TxXError{
+		Committed: make([]string, 0, len(keys)),
+		InDoubt:   make(map[string]string),
+	}
+
+	var errs []error
+	for _, k := range keys {
+		if _, e := db[k].ExecContext(context.WithoutCancel(ctx), "COMMIT PREPARED "+quote(gids[k])); e != nil {
+			errs = append(errs, fmt.Errorf("commit prepared(%s): %w", k, e))
+			report.InDoubt[k] = gids[k]
+			continue
+		}
+		report.Committed = append(report.Committed, k)
+	}
+	if len(errs) != 0 {
+		// 状态未决，不执行回调
+		report.Err = errors.Join(errs...)
+		return report
+	}
+	hooks.committed(outer, false)
+	return nil
+}
+
+// compensate 对已提交的库按逆序执行补偿
+func (e *TxXError) compensate(ctx context.Context, fns map[string]func(ctx context.Context) error) {
+	for _, k := range slices.Backward(e.Committed) {
+		fn, ok := fns[k]
+		if !ok {
+			continue
+		}
+		if e.Compensated == nil {
+			e.Compensated = make(map[string]error)
+		}
+		e.Compensated[k] = compensate(ctx, fn)
+	}
+}
+
+func compensate(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("compensate panic recovered: %+v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// commitOrder 返回提交顺序：order 中的库在前，其余按名称排序
+func commitOrder(db DB, order []string) []string {
+	keys := make([]string, 0, len(db))
+	seen := make(map[string]struct{}, len(db))
+	for _, k := range order {
+		if _, ok := db[k]; !ok {
+			continue
+		}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		keys = append(keys, k)
+	}
+
+	rest := make([]string, 0, len(db)-len(keys))
+	for k := range db {
+		if _, ok := seen[k]; !ok {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,127 @@
+package sqlkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitOrder(t *testing.T) {
+	db := DB{"c": nil, "a": nil, "b": nil, "d": nil}
+	assert.Equal(t, []string{"a", "b", "c", "d"}, commitOrder(db, nil))
+	assert.Equal(t, []string{"d", "b", "a", "c"}, commitOrder(db, []string{"d", "x", "b", "d"}))
+}
+
+func TestTransactionX(t *testing.T) {
+	ctx := context.Background()
+
+	a := newTestDB(t)
+	b, err := NewDB(&Config{
+		Driver: "sqlite3",
+		DSN:    "file:" + filepath.Join(t.TempDir(), "fk.db") + "?_foreign_keys=1",
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer b.Close()
+
+	_, err = b.Exec(`CREATE TABLE parent (id INTEGER PRIMARY KEY);
+CREATE TABLE child (pid INTEGER REFERENCES parent(id) DEFERRABLE INITIALLY DEFERRED);`)
+	assert.Nil(t, err)
+
+	var compensated bool
+
+	// b 提交时违反外键约束
+	err = TransactionX(ctx, DB{"a": a, "b": b}, func(ctx context.Context, tx TX) error {
+		if _, err := tx["a"].ExecContext(ctx, "INSERT INTO demo (name) VALUES ('hello')"); err != nil {
+			return err
+		}
+		_, err := tx["b"].ExecContext(ctx, "INSERT INTO child (pid) VALUES (1)")
+		return err
+	}, WithCompensate("a", func(ctx context.Context) error {
+		compensated = true
+		_, err := a.ExecContext(ctx, "DELETE FROM demo WHERE name = 'hello'")
+		return err
+	}))
+
+	var x *TxXError
+	if assert.True(t, errors.As(err, &x)) {
+		t.Log(x)
+		assert.Equal(t, []string{"a"}, x.Committed)
+		assert.Equal(t, "b", x.Failed)
+		assert.Nil(t, x.Compensated["a"])
+	}
+	assert.True(t, compensated)
+	assert.Equal(t, 0, countDemo(t, a))
+
+	// 提交顺序：b -> a
+	err = TransactionX(ctx, DB{"a": a, "b": b}, func(ctx context.Context, tx TX) error {
+		if _, err := tx["a"].ExecContext(ctx, "INSERT INTO demo (name) VALUES ('hello')"); err != nil {
+			return err
+		}
+		_, err := tx["b"].ExecContext(ctx, "INSERT INTO child (pid) VALUES (1)")
+		return err
+	}, WithCommitOrder("b", "a"))
+	if assert.True(t, errors.As(err, &x)) {
+		assert.Empty(t, x.Committed)
+		assert.Equal(t, []string{"a"}, x.RolledBack)
+	}
+	assert.Equal(t, 0, countDemo(t, a))
+
+	err = TransactionX(ctx, DB{"a": a, "b": b}, func(ctx context.Context, tx TX) error {
+		_, err := tx["a"].ExecContext(ctx, "INSERT INTO demo (name) VALUES ('hello')")
+		return err
+	}, WithTwoPhase())
+	assert.NotNil(t, err)
+
+	// a 加入外层事务：释放保存点不计为已提交，不执行补偿，回调待外层事务提交后执行
+	compensated = false
+	var hooked bool
+	err = Transaction(ctx, a, func(ctx context.Context, _ *sql.Tx) error {
+		err := TransactionX(ctx, DB{"a": a, "b": b}, func(ctx context.Context, tx TX) error {
+			AfterCommit(ctx, func(context.Context) { hooked = true })
+			_, err := tx["a"].ExecContext(ctx, "INSERT INTO demo (name) VALUES ('nested')")
+			return err
+		}, WithCompensate("a", func(ctx context.Context) error {
+			compensated = true
+			return nil
+		}))
+		assert.Nil(t, err)
+		assert.False(t, hooked)
+
+		err = TransactionX(ctx, DB{"a": a, "b": b}, func(ctx context.Context, tx TX) error {
+			if _, err := tx["a"].ExecContext(ctx, "INSERT INTO demo (name) VALUES ('hello')"); err != nil {
+				return err
+			}
+			_, err := tx["b"].ExecContext(ctx, "INSERT INTO child (pid) VALUES (1)")
+			return err
+		}, WithCompensate("a", func(ctx context.Context) error {
+			compensated = true
+			return nil
+		}))
+		if assert.True(t, errors.As(err, &x)) {
+			assert.Empty(t, x.Committed)
+			assert.Equal(t, []string{"a"}, x.Released)
+			assert.Equal(t, "b", x.Failed)
+		}
+		assert.False(t, compensated)
+		return err
+	})
+	assert.NotNil(t, err)
+	assert.False(t, hooked)
+	assert.Equal(t, 0, countDemo(t, a))
+
+	err = Transaction(ctx, a, func(ctx context.Context, _ *sql.Tx) error {
+		return TransactionX(ctx, DB{"a": a, "b": b}, func(ctx context.Context, tx TX) error {
+			AfterCommit(ctx, func(context.Context) { hooked = true })
+			_, err := tx["b"].ExecContext(ctx, "INSERT INTO parent (id) VALUES (1)")
+			return err
+		})
+	})
+	assert.Nil(t, err)
+	assert.True(t, hooked)
+}